@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// the name of the App passed to NewInstance
+const defaultComponentName = "app"
+
+var (
+	errEmptyComponentName = errors.New("component name is empty")
+	errNilComponent       = errors.New("component is nil")
+	errDuplicateComponent = errors.New("duplicate component")
+	errUnknownDependency  = errors.New("unknown dependency")
+	errDependencyCycle    = errors.New("dependency cycle")
+)
+
+// ReadyNotifier is implemented by components whose Start keeps running while they serve, e.g. a server.
+// Ready is closed once the component is ready, e.g. its listener is bound.
+// A component without it is ready as soon as its Start is called.
+type ReadyNotifier interface {
+	Ready() <-chan struct{}
+}
+
+type component struct {
+	name        string
+	app         App
+	dependsOn   []string
+	supervision *Supervision // overrides the Instance Supervision if set
+	phase       ShutdownPhase
+	started     chan struct{} // closed when Start is called the first time
+	startOnce   sync.Once
+	// the context passed to Start, it is canceled once the component is shut down in its phase
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newComponent(name string, app App) *component {
+	return &component{
+		name:    name,
+		app:     app,
+		phase:   PhaseDrain,
+		started: make(chan struct{}),
+	}
+}
+
+type ComponentOption func(*component)
+
+// DependsOn declares the components that must be ready before this component is started,
+// see ReadyNotifier, and shut down after it
+func DependsOn(names ...string) ComponentOption {
+	return func(c *component) {
+		c.dependsOn = append(c.dependsOn, names...)
+	}
+}
+
+// Supervise overrides the Instance Supervision for this component
+func Supervise(s Supervision) ComponentOption {
+	return func(c *component) {
+		c.supervision = &s
+	}
+}
+
+// InPhase sets the shutdown phase in which the component is shut down, PhaseDrain by default.
+// PhasePreStop is not a component phase and is ignored.
+func InPhase(phase ShutdownPhase) ComponentOption {
+	return func(c *component) {
+		if phase > PhasePreStop && phase <= PhaseCloseResources {
+			c.phase = phase
+		}
+	}
+}
+
+// ComponentError reports which component failed and why
+type ComponentError struct {
+	Name string
+	Err  error
+}
+
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("component %s: %v", e.Name, e.Err)
+}
+
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// sortComponents returns the components in topological order, dependencies first.
+// Components without ordering constraints keep their registration order.
+func sortComponents(components []*component) ([]*component, error) {
+	byName := make(map[string]*component, len(components))
+	for _, c := range components {
+		if c.name == "" {
+			return nil, errEmptyComponentName
+		}
+		if c.app == nil {
+			return nil, fmt.Errorf("%w: %s", errNilComponent, c.name)
+		}
+		if _, ok := byName[c.name]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicateComponent, c.name)
+		}
+		byName[c.name] = c
+	}
+
+	inDegree := make(map[string]int, len(components))
+	dependents := make(map[string][]string, len(components))
+	for _, c := range components {
+		for _, dep := range c.dependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("%w: %s depends on %s", errUnknownDependency, c.name, dep)
+			}
+			inDegree[c.name]++
+			dependents[dep] = append(dependents[dep], c.name)
+		}
+	}
+
+	sorted := make([]*component, 0, len(components))
+	visited := make(map[string]bool, len(components))
+	for len(sorted) < len(components) {
+		progress := false
+		// scan in registration order to keep the result deterministic
+		for _, c := range components {
+			if visited[c.name] || inDegree[c.name] > 0 {
+				continue
+			}
+			visited[c.name] = true
+			sorted = append(sorted, c)
+			for _, dependent := range dependents[c.name] {
+				inDegree[dependent]--
+			}
+			progress = true
+		}
+		if !progress {
+			var cycle []string
+			for _, c := range components {
+				if !visited[c.name] {
+					cycle = append(cycle, c.name)
+				}
+			}
+			return nil, fmt.Errorf("%w between %v", errDependencyCycle, cycle)
+		}
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	stops []string
+}
+
+func (r *recorder) app(name string, shutdownErr error) App {
+	return &mockApp{
+		shutdownFunc: func() error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.stops = append(r.stops, name)
+			return shutdownErr
+		},
+	}
+}
+
+// readyApp is a mockApp which is ready once its Start has run for delay
+type readyApp struct {
+	mockApp
+	delay time.Duration
+	ready chan struct{}
+}
+
+func newReadyApp(delay time.Duration) *readyApp {
+	return &readyApp{delay: delay, ready: make(chan struct{})}
+}
+
+func (a *readyApp) Start(ctx context.Context) {
+	select {
+	case <-time.After(a.delay):
+		close(a.ready)
+	case <-ctx.Done():
+	}
+}
+
+func (a *readyApp) Ready() <-chan struct{} {
+	return a.ready
+}
+
+func Test_sortComponents(t *testing.T) {
+	app := &mockApp{}
+
+	testCases := []struct {
+		name       string
+		components []*component
+		want       []string
+		wantErr    error
+	}{
+		{
+			name: "test dependencies first, registration order otherwise",
+			components: []*component{
+				{name: "http", app: app, dependsOn: []string{"mysql", "kafka"}},
+				{name: "kafka", app: app},
+				{name: "cron", app: app, dependsOn: []string{"mysql"}},
+				{name: "mysql", app: app},
+			},
+			want: []string{"kafka", "mysql", "http", "cron"},
+		},
+		{
+			name: "test unknown dependency",
+			components: []*component{
+				{name: "http", app: app, dependsOn: []string{"mysql"}},
+			},
+			wantErr: errUnknownDependency,
+		},
+		{
+			name: "test duplicate component",
+			components: []*component{
+				{name: "http", app: app},
+				{name: "http", app: app},
+			},
+			wantErr: errDuplicateComponent,
+		},
+		{
+			name: "test dependency cycle",
+			components: []*component{
+				{name: "a", app: app, dependsOn: []string{"b"}},
+				{name: "b", app: app, dependsOn: []string{"a"}},
+			},
+			wantErr: errDependencyCycle,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorted, err := sortComponents(tc.components)
+			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("sortComponents() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			got := make([]string, 0, len(sorted))
+			for _, c := range sorted {
+				got = append(got, c.name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("sortComponents() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Instance_ShutdownOrder(t *testing.T) {
+	rec := &recorder{}
+	errKafka := errors.New("kafka close failed")
+
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("http", rec.app("http", nil), DependsOn("mysql", "kafka")),
+		WithComponent("kafka", rec.app("kafka", errKafka)),
+		WithComponent("mysql", rec.app("mysql", nil)),
+	)
+	components, err := sortComponents(instance.components)
+	if err != nil {
+		t.Fatalf("sortComponents() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = instance.shutdown(ctx, components)
+	var componentErr *ComponentError
+	if !errors.As(err, &componentErr) || componentErr.Name != "kafka" || !errors.Is(err, errKafka) {
+		t.Errorf("shutdown() error = %v, want kafka component error", err)
+	}
+
+	want := []string{"http", "mysql", "kafka"}
+	if !reflect.DeepEqual(rec.stops, want) {
+		t.Errorf("shutdown order = %v, want %v", rec.stops, want)
+	}
+}
+
+func Test_Instance_StartOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mysql         = newReadyApp(time.Millisecond * 20)
+		kafkaStarted  atomic.Bool
+		httpStarted   = make(chan struct{})
+		consumerReady atomic.Bool
+		signalCh      = make(chan os.Signal, 1)
+	)
+
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("http", &mockApp{startFunc: func() {
+			select {
+			case <-mysql.ready:
+			default:
+				t.Error("http started before mysql is ready")
+			}
+			if !consumerReady.Load() {
+				t.Error("http started before consumer")
+			}
+			close(httpStarted)
+		}}, DependsOn("mysql", "consumer")),
+		WithComponent("consumer", &mockApp{startFunc: func() {
+			if !kafkaStarted.Load() {
+				t.Error("consumer started before kafka")
+			}
+			consumerReady.Store(true)
+		}}, DependsOn("kafka")),
+		WithComponent("mysql", mysql),
+		WithComponent("kafka", &mockApp{startFunc: func() { kafkaStarted.Store(true) }}),
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	go func() {
+		<-httpStarted
+		signalCh <- syscall.SIGTERM
+	}()
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func Test_Instance_SignalWhileStarting(t *testing.T) {
+	t.Parallel()
+
+	rec := &recorder{}
+	signalCh := make(chan os.Signal, 1)
+	started := false
+
+	instance := NewInstance(context.Background(), nil,
+		// never ready
+		WithComponent("mysql", newReadyApp(time.Hour)),
+		WithComponent("kafka", rec.app("kafka", nil)),
+		WithComponent("http", &mockApp{startFunc: func() { started = true }}, DependsOn("mysql")),
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	instance.OnStarted(func(Event) { t.Error("OnStarted called while mysql is not ready") })
+	signalCh <- syscall.SIGTERM
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if started {
+		t.Error("http started while mysql is not ready")
+	}
+	if want := []string{"kafka"}; !reflect.DeepEqual(rec.stops, want) {
+		t.Errorf("stopped = %v, want the started components %v", rec.stops, want)
+	}
+}
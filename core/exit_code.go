@@ -0,0 +1,42 @@
+package core
+
+import "errors"
+
+var (
+	// ErrInvalidComponents is returned when components have duplicate names, unknown dependencies or cycles
+	ErrInvalidComponents = errors.New("invalid components")
+	// ErrComponentFailed is returned when a component panics or exhausts its restart budget
+	ErrComponentFailed = errors.New("component failed")
+	// ErrShutdownFailed is returned when a component returns an error from Shutdown
+	ErrShutdownFailed = errors.New("shutdown failed")
+	// ErrShutdownTimeout is returned when components are not shut down within the graceful shutdown timeout
+	ErrShutdownTimeout = errors.New("shutdown timed out")
+)
+
+// process exit codes returned by ExitCode
+const (
+	ExitOK              = 0
+	ExitFailure         = 1
+	ExitStartFailed     = 2
+	ExitShutdownFailed  = 3
+	ExitShutdownTimeout = 4
+)
+
+// ExitCode maps the error returned by Run to a process exit code.
+// A start failure takes precedence because it is the reason of the shutdown.
+//
+//	os.Exit(core.ExitCode(instance.Run(ctx)))
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrInvalidComponents), errors.Is(err, ErrComponentFailed):
+		return ExitStartFailed
+	case errors.Is(err, ErrShutdownTimeout):
+		return ExitShutdownTimeout
+	case errors.Is(err, ErrShutdownFailed):
+		return ExitShutdownFailed
+	default:
+		return ExitFailure
+	}
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_Instance_Run(t *testing.T) {
+	t.Parallel()
+
+	errShutdown := errors.New("st went wrong")
+
+	testCases := []struct {
+		name     string
+		app      *mockApp
+		signal   bool
+		wantErr  error
+		wantCode int
+	}{
+		{
+			name:     "test graceful shutdown with signal",
+			app:      &mockApp{},
+			signal:   true,
+			wantCode: ExitOK,
+		},
+		{
+			name: "test component panic",
+			app: &mockApp{
+				startFunc: func() {
+					panic("st went wrong")
+				},
+			},
+			wantErr:  ErrComponentFailed,
+			wantCode: ExitStartFailed,
+		},
+		{
+			name:     "test shutdown failed",
+			app:      &mockApp{shutdownErr: errShutdown},
+			signal:   true,
+			wantErr:  ErrShutdownFailed,
+			wantCode: ExitShutdownFailed,
+		},
+		{
+			name: "test shutdown timed out",
+			app: &mockApp{
+				shutdownFunc: func() error {
+					time.Sleep(time.Millisecond * 50)
+					return context.DeadlineExceeded
+				},
+			},
+			signal:   true,
+			wantErr:  ErrShutdownTimeout,
+			wantCode: ExitShutdownTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			signalCh := make(chan os.Signal, 1)
+			if tc.signal {
+				signalCh <- syscall.SIGTERM
+			}
+
+			instance := NewInstance(context.Background(), tc.app,
+				WithSignal(signalCh),
+				WithGracefulShutdown(time.Millisecond*10),
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			)
+
+			err := instance.Run(context.Background())
+			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := ExitCode(err); got != tc.wantCode {
+				t.Errorf("ExitCode() = %d, want %d", got, tc.wantCode)
+			}
+		})
+	}
+}
+
+func Test_Instance_Run_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	instance := NewInstance(context.Background(), &mockApp{startFunc: cancel},
+		WithSignal(make(chan os.Signal)),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	if err := instance.Run(ctx); err != nil {
+		t.Errorf("Run() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"os"
+	"time"
+)
+
+// Event describes a lifecycle transition of an Instance
+type Event struct {
+	// Component is set on OnComponentStopped
+	Component string
+	// Phase is the shutdown phase in which the component is stopped, set on OnComponentStopped
+	Phase ShutdownPhase
+	// Signal is the signal which triggers the shutdown, nil if it is triggered by a context or a failure
+	Signal os.Signal
+	// Elapsed is the duration until every component is ready on OnStarted, the duration of the component Shutdown
+	// on OnComponentStopped and the whole shutdown duration on OnShutdownDone
+	Elapsed time.Duration
+	// Err is the component failure on OnShutdownBegin and the shutdown error on
+	// OnComponentStopped and OnShutdownDone
+	Err error
+}
+
+type hookKind int
+
+const (
+	hookStarting hookKind = iota
+	hookStarted
+	hookShutdownBegin
+	hookComponentStopped
+	hookShutdownDone
+)
+
+func (k hookKind) String() string {
+	return [...]string{"OnStarting", "OnStarted", "OnShutdownBegin", "OnComponentStopped", "OnShutdownDone"}[k]
+}
+
+// OnStarting registers a hook called before the components are started
+func (i *Instance) OnStarting(fn func(Event)) {
+	i.addHook(hookStarting, fn)
+}
+
+// OnStarted registers a hook called once every component is ready, see ReadyNotifier.
+// It is not called if the instance stops while starting.
+func (i *Instance) OnStarted(fn func(Event)) {
+	i.addHook(hookStarted, fn)
+}
+
+// OnShutdownBegin registers a hook called when the shutdown is triggered, before the pre-stop phase
+func (i *Instance) OnShutdownBegin(fn func(Event)) {
+	i.addHook(hookShutdownBegin, fn)
+}
+
+// OnComponentStopped registers a hook called after each component Shutdown returns
+func (i *Instance) OnComponentStopped(fn func(Event)) {
+	i.addHook(hookComponentStopped, fn)
+}
+
+// OnShutdownDone registers a hook called once every component is shut down
+func (i *Instance) OnShutdownDone(fn func(Event)) {
+	i.addHook(hookShutdownDone, fn)
+}
+
+func (i *Instance) addHook(kind hookKind, fn func(Event)) {
+	if fn == nil {
+		return
+	}
+
+	i.hooksMu.Lock()
+	defer i.hooksMu.Unlock()
+	if i.hooks == nil {
+		i.hooks = make(map[hookKind][]func(Event))
+	}
+	i.hooks[kind] = append(i.hooks[kind], fn)
+}
+
+// emit calls the hooks synchronously in registration order, a panicking hook is only logged
+func (i *Instance) emit(kind hookKind, event Event) {
+	i.hooksMu.Lock()
+	hooks := i.hooks[kind]
+	i.hooksMu.Unlock()
+
+	for _, fn := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					i.logger.Error("recover", "reason", r, "hook", kind.String())
+				}
+			}()
+			fn(event)
+		}()
+	}
+}
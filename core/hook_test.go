@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_Instance_Hooks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got      []string
+		stopped  []Event
+		done     Event
+		signalCh = make(chan os.Signal, 1)
+		errKafka = errors.New("kafka close failed")
+	)
+	signalCh <- syscall.SIGTERM
+
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("kafka", &mockApp{shutdownErr: errKafka}, InPhase(PhaseCloseResources)),
+		WithComponent("http", &mockApp{}, DependsOn("kafka"), InPhase(PhaseStopIntake)),
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	instance.OnStarting(func(e Event) { got = append(got, "starting") })
+	instance.OnStarted(func(e Event) { got = append(got, "started") })
+	instance.OnShutdownBegin(func(e Event) {
+		got = append(got, "shutdown begin")
+		if e.Signal != syscall.SIGTERM {
+			t.Errorf("OnShutdownBegin signal = %v, want %v", e.Signal, syscall.SIGTERM)
+		}
+	})
+	instance.OnComponentStopped(func(e Event) {
+		got = append(got, "stopped "+e.Component)
+		stopped = append(stopped, e)
+	})
+	instance.OnShutdownDone(func(e Event) {
+		got = append(got, "shutdown done")
+		done = e
+	})
+	// a panicking hook does not break the lifecycle
+	instance.OnStarted(func(e Event) { panic("st went wrong") })
+
+	if err := instance.Run(context.Background()); !errors.Is(err, errKafka) {
+		t.Fatalf("Run() error = %v, want %v", err, errKafka)
+	}
+
+	want := []string{"starting", "started", "shutdown begin", "stopped http", "stopped kafka", "shutdown done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hooks = %v, want %v", got, want)
+	}
+	if stopped[0].Phase != PhaseStopIntake || stopped[1].Phase != PhaseCloseResources {
+		t.Errorf("stopped phases = %s, %s", stopped[0].Phase, stopped[1].Phase)
+	}
+	if !errors.Is(stopped[1].Err, errKafka) || !errors.Is(done.Err, ErrShutdownFailed) {
+		t.Errorf("stopped error = %v, done error = %v", stopped[1].Err, done.Err)
+	}
+}
+
+func Test_Instance_OnStartedElapsed(t *testing.T) {
+	t.Parallel()
+
+	const delay = time.Millisecond * 20
+	signalCh := make(chan os.Signal, 1)
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("mysql", newReadyApp(delay)),
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	var elapsed time.Duration
+	instance.OnStarted(func(e Event) {
+		elapsed = e.Elapsed
+		signalCh <- syscall.SIGTERM
+	})
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed < delay {
+		t.Errorf("OnStarted elapsed = %s, want at least the readiness delay %s", elapsed, delay)
+	}
+}
@@ -2,20 +2,35 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+
+	"github.com/ngoctd314/common/env"
+	"github.com/ngoctd314/common/gctx"
 )
 
 type Instance struct {
 	baseCtx         context.Context
-	cancelFunc      func()
-	app             App
+	cancelFunc      context.CancelCauseFunc
+	signalCh        <-chan os.Signal // replaces the OS signals if set
+	components      []*component
 	logger          Logger
 	shutdownTimeout time.Duration // graceful shutdown timeout
+	shutdownPhases  ShutdownPhases
+	ready           atomic.Bool
+	supervision     Supervision                         // the default Supervision of components
+	reloadConfig    func() (rollback func(), err error) // re-reads the configuration, env.ReloadWithRollback by default
+	reloadMu        sync.Mutex
+	hooks           map[hookKind][]func(Event)
+	hooksMu         sync.Mutex
+	tasks           *gctx.Tasks // background tasks waited at the end of PhaseDrain
 }
 
 type App interface {
@@ -24,15 +39,21 @@ type App interface {
 }
 type InstanceOption func(*Instance)
 
+// NewInstance creates an Instance. app is registered as the component named "app",
+// pass nil and use WithComponent to register several components instead.
 func NewInstance(ctx context.Context, app App, opts ...InstanceOption) *Instance {
-	baseCtx, cancel := context.WithCancel(ctx)
+	baseCtx, cancel := context.WithCancelCause(ctx)
 
 	instance := &Instance{
 		baseCtx:         baseCtx,
 		cancelFunc:      cancel,
-		app:             app,
 		logger:          slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 		shutdownTimeout: time.Second * 10, // the default graceful shutdown timeout is 10s
+		reloadConfig:    env.ReloadWithRollback,
+		tasks:           gctx.DefaultTasks(),
+	}
+	if app != nil {
+		instance.components = append(instance.components, newComponent(defaultComponentName, app))
 	}
 
 	for _, opt := range opts {
@@ -42,39 +63,151 @@ func NewInstance(ctx context.Context, app App, opts ...InstanceOption) *Instance
 	return instance
 }
 
-// Bootstrap start app and handle graceful shutdown
+// Bootstrap start components in dependency order and handle graceful shutdown,
+// the result is only logged, use Run to act on it
 func (i *Instance) Bootstrap() {
-	// start instance
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				i.logger.Error("recover", "reason", r)
-				i.cancelFunc()
-			}
-		}()
-		i.app.Start(i.baseCtx)
-	}()
+	_ = i.Run(context.Background())
+}
 
-	// handle graceful shutdown
-	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+// Run starts components in dependency order, a component is started once its dependencies are ready,
+// see ReadyNotifier. Then it waits for a termination signal, ctx to be done or a component to fail
+// and shuts the started components down. SIGHUP reloads the configuration instead of terminating.
+// The returned error wraps ErrInvalidComponents, ErrComponentFailed, ErrShutdownFailed
+// or ErrShutdownTimeout, ExitCode maps it to a process exit code.
+func (i *Instance) Run(ctx context.Context) error {
+	components, err := sortComponents(i.components)
+	if err != nil {
+		i.logger.Error("invalid components", "err", err)
+		return fmt.Errorf("%w: %w", ErrInvalidComponents, err)
+	}
 
-	// wait for termination signal or context done
-	select {
-	case v := <-signalCh:
-		i.logger.Warn(fmt.Sprintf("receive os.Signal: %s", v))
-	case <-i.baseCtx.Done():
+	// handle graceful shutdown, listen before starting so no signal is missed
+	signalCh := i.signalCh
+	if signalCh == nil {
+		osSignalCh := make(chan os.Signal, 1)
+		signal.Notify(osSignalCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		defer signal.Stop(osSignalCh)
+		signalCh = osSignalCh
+	}
+
+	// start components, dependencies first
+	startAt := time.Now()
+	i.emit(hookStarting, Event{})
+	components, sig, ok := i.start(ctx, components, signalCh)
+	if ok {
+		i.ready.Store(true)
+		i.emit(hookStarted, Event{Elapsed: time.Since(startAt)})
+		sig = i.wait(ctx, signalCh)
+	}
+
+	// a component failure is the cause of the canceled base context
+	var startErr error
+	if cause := context.Cause(i.baseCtx); errors.Is(cause, ErrComponentFailed) {
+		startErr = cause
 	}
 
 	now := time.Now()
+	i.emit(hookShutdownBegin, Event{Signal: sig, Err: startErr})
 
-	shutdownCtx, cancel := context.WithTimeout(i.baseCtx, i.shutdownTimeout)
+	// the base context is canceled during shutdown, keep its values only
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(i.baseCtx), i.shutdownTimeout)
 	defer cancel()
 
-	// create a WaitGroup to keep track of shutdown goroutines
-	if err := i.app.Shutdown(shutdownCtx); err != nil {
-		i.logger.Error("error occur when Shutdown", "err", err)
-		return
+	err = i.shutdown(shutdownCtx, components)
+	i.emit(hookShutdownDone, Event{Signal: sig, Elapsed: time.Since(now), Err: err})
+	if err != nil {
+		return errors.Join(startErr, err)
 	}
 	i.logger.Info(fmt.Sprintf("shutdown complete after %f seconds", time.Since(now).Seconds()))
+
+	return startErr
+}
+
+// start starts each component once its dependencies are ready and waits for every component to be ready.
+// It stops on a termination signal, ctx to be done or a component failure, then ok is false.
+// It returns the started components, in dependency order, and the termination signal if any.
+func (i *Instance) start(ctx context.Context, components []*component, signalCh <-chan os.Signal) (started []*component, sig os.Signal, ok bool) {
+	byName := make(map[string]*component, len(components))
+	for _, c := range components {
+		byName[c.name] = c
+	}
+
+	for _, c := range components {
+		for _, dep := range c.dependsOn {
+			if sig, ok = i.waitReady(ctx, byName[dep], signalCh); !ok {
+				return started, sig, false
+			}
+		}
+		// the instance stopping does not cancel the component, its shutdown phase does
+		c.ctx, c.cancel = context.WithCancel(context.WithoutCancel(i.baseCtx))
+		go i.supervise(c)
+		started = append(started, c)
+	}
+	for _, c := range components {
+		if sig, ok = i.waitReady(ctx, c, signalCh); !ok {
+			return started, sig, false
+		}
+	}
+
+	return started, nil, true
+}
+
+// waitReady blocks until c is ready, see ReadyNotifier, or the startup stops.
+// SIGHUP is ignored until c is ready.
+func (i *Instance) waitReady(ctx context.Context, c *component, signalCh <-chan os.Signal) (os.Signal, bool) {
+	// Start is called right after the goroutine of c is scheduled
+	select {
+	case <-c.started:
+	case <-i.baseCtx.Done():
+		return nil, false
+	}
+
+	notifier, ok := c.app.(ReadyNotifier)
+	if !ok {
+		return nil, true
+	}
+	ready := notifier.Ready()
+	for {
+		select {
+		case <-ready:
+			return nil, true
+		case v := <-signalCh:
+			if v == syscall.SIGHUP {
+				i.logger.Warn(fmt.Sprintf("receive os.Signal: %s, ignored while starting", v))
+				continue
+			}
+			i.logger.Warn(fmt.Sprintf("receive os.Signal: %s", v))
+			return v, false
+		case <-ctx.Done():
+			return nil, false
+		case <-i.baseCtx.Done():
+			return nil, false
+		}
+	}
+}
+
+// wait blocks until a termination signal, ctx is done or a component fails,
+// it returns the termination signal if any
+func (i *Instance) wait(ctx context.Context, signalCh <-chan os.Signal) os.Signal {
+	for {
+		select {
+		case v := <-signalCh:
+			if v == syscall.SIGHUP {
+				i.logger.Info(fmt.Sprintf("receive os.Signal: %s", v))
+				_ = i.Reload(i.baseCtx)
+				continue
+			}
+			i.logger.Warn(fmt.Sprintf("receive os.Signal: %s", v))
+			return v
+		case <-ctx.Done():
+		case <-i.baseCtx.Done():
+		}
+		return nil
+	}
+}
+
+// Ready reports whether the components are started and the instance is not shutting down,
+// it turns false as soon as the pre-stop phase begins
+func (i *Instance) Ready() bool {
+	return i.ready.Load()
 }
@@ -1,6 +1,16 @@
 package core
 
-import "time"
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/ngoctd314/common/gctx"
+	"github.com/ngoctd314/common/health"
+)
+
+var errNotReady = errors.New("instance is starting or shutting down")
 
 func WithLogger(logger Logger) InstanceOption {
 	return func(i *Instance) {
@@ -17,3 +27,70 @@ func WithGracefulShutdown(timeout time.Duration) InstanceOption {
 		}
 	}
 }
+
+// WithComponent registers a named App, it is started after and shut down before
+// the components it depends on
+func WithComponent(name string, app App, opts ...ComponentOption) InstanceOption {
+	return func(i *Instance) {
+		c := newComponent(name, app)
+		for _, opt := range opts {
+			opt(c)
+		}
+		i.components = append(i.components, c)
+	}
+}
+
+// WithSupervision sets how components are restarted when their Start panics or returns,
+// by default a panic shuts the whole instance down
+func WithSupervision(s Supervision) InstanceOption {
+	return func(i *Instance) {
+		i.supervision = s
+	}
+}
+
+// WithSignal replaces SIGINT and SIGTERM of the process with the signals received from ch,
+// it lets tests trigger a graceful shutdown without sending real signals
+func WithSignal(ch <-chan os.Signal) InstanceOption {
+	return func(i *Instance) {
+		if ch != nil {
+			i.signalCh = ch
+		}
+	}
+}
+
+// WithShutdownPhases splits the graceful shutdown into ordered phases,
+// the phase timeouts are carved out of the WithGracefulShutdown timeout
+func WithShutdownPhases(phases ShutdownPhases) InstanceOption {
+	return func(i *Instance) {
+		i.shutdownPhases = phases
+	}
+}
+
+// WithHealth registers a critical readiness check named "instance" into registry,
+// it fails until the components are started and as soon as the shutdown begins
+func WithHealth(registry *health.Registry) InstanceOption {
+	return func(i *Instance) {
+		if registry == nil {
+			return
+		}
+		registry.RegisterReadiness(health.Check{
+			Name: "instance",
+			Probe: func(ctx context.Context) error {
+				if !i.Ready() {
+					return errNotReady
+				}
+				return nil
+			},
+			Critical: true,
+		})
+	}
+}
+
+// WithTasks sets the background tasks waited at the end of PhaseDrain, gctx.DefaultTasks by default
+func WithTasks(tasks *gctx.Tasks) InstanceOption {
+	return func(i *Instance) {
+		if tasks != nil {
+			i.tasks = tasks
+		}
+	}
+}
@@ -116,7 +116,7 @@ func Test_Instance_BootStrap(t *testing.T) {
 			},
 			wantLog: []string{
 				"level=WARN msg=\"receive os.Signal: terminated\"",
-				"level=ERROR msg=\"error occur when Shutdown\"",
+				"level=ERROR msg=\"component shutdown failed\" component=app",
 			},
 		},
 	}
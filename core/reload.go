@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Reloader is implemented by components that apply configuration changes without a restart.
+// Reload is called after the configuration is re-read, the component reads the new values
+// from env. Returning an error rejects them and the component keeps its current configuration,
+// the previous configuration is restored in env and Reload is called again on the components
+// which accepted the new values.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// Reload re-reads the configuration and fans the reload out to components implementing Reloader,
+// dependencies first. It is triggered by SIGHUP while Run is waiting for termination.
+// The returned error joins the ComponentError of every component rejecting the new values,
+// then the previous configuration is kept.
+func (i *Instance) Reload(ctx context.Context) error {
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	components, err := sortComponents(i.components)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidComponents, err)
+	}
+
+	rollback, err := i.reloadConfig()
+	if err != nil {
+		i.logger.Error("reload config failed, keep the current config", "err", err)
+		return err
+	}
+
+	var (
+		errGroup error
+		accepted []*component
+	)
+	for _, c := range components {
+		reloader, ok := c.app.(Reloader)
+		if !ok {
+			continue
+		}
+		if err := reloader.Reload(ctx); err != nil {
+			i.logger.Warn("component rejected the new config, keep the current config", "component", c.name, "err", err)
+			errGroup = errors.Join(errGroup, &ComponentError{Name: c.name, Err: err})
+			continue
+		}
+		accepted = append(accepted, c)
+	}
+	if errGroup == nil {
+		i.logger.Info("config reloaded")
+		return nil
+	}
+
+	// restore the previous config, the components which accepted the new one apply it again
+	rollback()
+	for _, c := range accepted {
+		if err := c.app.(Reloader).Reload(ctx); err != nil {
+			i.logger.Error("component failed to restore the previous config", "component", c.name, "err", err)
+		}
+	}
+	i.logger.Info("previous config restored")
+
+	return errGroup
+}
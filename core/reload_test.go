@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type reloadableApp struct {
+	mockApp
+	reloads   int
+	reloadErr error
+}
+
+func (a *reloadableApp) Reload(ctx context.Context) error {
+	a.reloads++
+	return a.reloadErr
+}
+
+func Test_Instance_Reload(t *testing.T) {
+	t.Parallel()
+
+	errReject := errors.New("rate limit must be positive")
+	errRead := errors.New("invalid yaml")
+
+	testCases := []struct {
+		name         string
+		readErr      error
+		rejectErr    error
+		wantReloads  int
+		wantErr      error
+		wantRejected bool
+		wantRollback bool
+	}{
+		{
+			name:        "test all components accept",
+			wantReloads: 1,
+		},
+		{
+			name:      "test component rejects",
+			rejectErr: errReject,
+			// the accepting component applies the previous config again
+			wantReloads:  2,
+			wantErr:      errReject,
+			wantRejected: true,
+			wantRollback: true,
+		},
+		{
+			name:    "test config cannot be read",
+			readErr: errRead,
+			wantErr: errRead,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			accepting := &reloadableApp{}
+			rejecting := &reloadableApp{reloadErr: tc.rejectErr}
+			instance := NewInstance(context.Background(), nil,
+				WithComponent("http", accepting),
+				WithComponent("ratelimit", rejecting),
+				WithComponent("mysql", &mockApp{}),
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			)
+			rolledBack := false
+			instance.reloadConfig = func() (func(), error) {
+				return func() { rolledBack = true }, tc.readErr
+			}
+
+			err := instance.Reload(context.Background())
+			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			var componentErr *ComponentError
+			if rejected := errors.As(err, &componentErr); rejected != tc.wantRejected {
+				t.Errorf("Reload() rejected = %v, want %v", rejected, tc.wantRejected)
+			}
+			if accepting.reloads != tc.wantReloads || rejecting.reloads != min(tc.wantReloads, 1) {
+				t.Errorf("reloads = %d, %d, want %d", accepting.reloads, rejecting.reloads, tc.wantReloads)
+			}
+			if rolledBack != tc.wantRollback {
+				t.Errorf("rolled back = %v, want %v", rolledBack, tc.wantRollback)
+			}
+		})
+	}
+}
+
+func Test_Instance_Run_SIGHUP(t *testing.T) {
+	t.Parallel()
+
+	app := &reloadableApp{}
+	signalCh := make(chan os.Signal, 2)
+	signalCh <- syscall.SIGHUP
+	signalCh <- syscall.SIGTERM
+
+	instance := NewInstance(context.Background(), app,
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	instance.reloadConfig = func() (func(), error) { return func() {}, nil }
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if app.reloads != 1 {
+		t.Errorf("reloads = %d, want 1", app.reloads)
+	}
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ShutdownPhase int
+
+const (
+	// PhasePreStop keeps serving while the instance is removed from load balancers,
+	// readiness is already failing and no component is shut down yet
+	PhasePreStop ShutdownPhase = iota
+	// PhaseStopIntake stops accepting new work, e.g. HTTP servers and consumers
+	PhaseStopIntake
+	// PhaseDrain waits for in-flight work to finish, this is the default phase of a component,
+	// the background tasks of gctx are waited once the components of the phase are shut down
+	PhaseDrain
+	// PhaseCloseResources closes clients, e.g. DB pools and Kafka writers
+	PhaseCloseResources
+)
+
+// the phases in which components are shut down
+var componentPhases = []ShutdownPhase{PhaseStopIntake, PhaseDrain, PhaseCloseResources}
+
+func (p ShutdownPhase) String() string {
+	switch p {
+	case PhasePreStop:
+		return "pre-stop"
+	case PhaseStopIntake:
+		return "stop-intake"
+	case PhaseDrain:
+		return "drain"
+	case PhaseCloseResources:
+		return "close-resources"
+	default:
+		return fmt.Sprintf("ShutdownPhase(%d)", int(p))
+	}
+}
+
+// ShutdownPhases carves the graceful shutdown timeout into phases.
+// Each phase ends at its own timeout or at the graceful shutdown deadline, whichever comes first,
+// a zero timeout lets the phase use what is left of the graceful shutdown timeout.
+type ShutdownPhases struct {
+	PreStopDelay   time.Duration
+	StopIntake     time.Duration
+	Drain          time.Duration
+	CloseResources time.Duration
+}
+
+func (p ShutdownPhases) timeout(phase ShutdownPhase) time.Duration {
+	switch phase {
+	case PhaseStopIntake:
+		return p.StopIntake
+	case PhaseDrain:
+		return p.Drain
+	case PhaseCloseResources:
+		return p.CloseResources
+	default:
+		return 0
+	}
+}
+
+// shutdown flips readiness, waits for the pre-stop delay and stops components phase by phase,
+// within a phase components are stopped in reverse dependency order.
+// All phases share the deadline of ctx.
+func (i *Instance) shutdown(ctx context.Context, components []*component) error {
+	i.ready.Store(false)
+
+	if delay := i.shutdownPhases.PreStopDelay; delay > 0 {
+		i.logger.Info("pre-stop, keep serving before shutting components down", "delay", delay.String())
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+	// from now on, components are neither started nor restarted,
+	// the context of a component is canceled only when its phase shuts it down
+	i.cancelFunc(context.Canceled)
+
+	var (
+		errGroup error
+		timedOut bool
+	)
+	for _, phase := range componentPhases {
+		phaseCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := i.shutdownPhases.timeout(phase); timeout > 0 {
+			phaseCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		err := i.shutdownPhase(phaseCtx, phase, components)
+		if phase == PhaseDrain {
+			err = errors.Join(err, i.waitTasks(phaseCtx))
+		}
+		if err != nil {
+			errGroup = errors.Join(errGroup, err)
+			timedOut = timedOut || errors.Is(phaseCtx.Err(), context.DeadlineExceeded)
+		}
+		cancel()
+	}
+
+	switch {
+	case errGroup == nil:
+		return nil
+	case timedOut:
+		return fmt.Errorf("%w: %w", ErrShutdownTimeout, errGroup)
+	default:
+		return fmt.Errorf("%w: %w", ErrShutdownFailed, errGroup)
+	}
+}
+
+func (i *Instance) shutdownPhase(ctx context.Context, phase ShutdownPhase, components []*component) error {
+	var errGroup error
+	for idx := len(components) - 1; idx >= 0; idx-- {
+		c := components[idx]
+		if c.phase != phase {
+			continue
+		}
+		now := time.Now()
+		err := c.app.Shutdown(ctx)
+		if c.cancel != nil {
+			c.cancel()
+		}
+		i.emit(hookComponentStopped, Event{Component: c.name, Phase: phase, Elapsed: time.Since(now), Err: err})
+		if err != nil {
+			i.logger.Error("component shutdown failed", "component", c.name, "phase", phase.String(), "err", err)
+			errGroup = errors.Join(errGroup, &ComponentError{Name: c.name, Err: err})
+		}
+	}
+
+	return errGroup
+}
+
+// waitTasks waits for the background tasks spawned by the drained components, e.g. by HTTP handlers
+func (i *Instance) waitTasks(ctx context.Context) error {
+	if running := i.tasks.Running(); running > 0 {
+		i.logger.Info("wait for background tasks", "running", running)
+	}
+	if err := i.tasks.Wait(ctx); err != nil {
+		i.logger.Error("background tasks shutdown failed", "err", err)
+		return err
+	}
+	return nil
+}
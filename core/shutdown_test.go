@@ -0,0 +1,215 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ngoctd314/common/gctx"
+	"github.com/ngoctd314/common/health"
+)
+
+func Test_Instance_ShutdownPhases(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rec      = &recorder{}
+		instance *Instance
+		preStop  = time.Millisecond * 20
+		signalAt time.Time
+		registry = health.NewRegistry()
+	)
+
+	intake := &mockApp{
+		shutdownFunc: func() error {
+			if instance.Ready() {
+				t.Error("Ready() = true, want false during shutdown")
+			}
+			if report := registry.Ready(context.Background()); report.Status != health.StatusDown {
+				t.Errorf("readiness = %s, want %s during shutdown", report.Status, health.StatusDown)
+			}
+			if elapsed := time.Since(signalAt); elapsed < preStop {
+				t.Errorf("stop-intake began after %s, want after pre-stop delay %s", elapsed, preStop)
+			}
+			return rec.app("http", nil).Shutdown(context.Background())
+		},
+	}
+	signalCh := make(chan os.Signal, 1)
+	instance = NewInstance(context.Background(), nil,
+		WithComponent("mysql", rec.app("mysql", nil), InPhase(PhaseCloseResources)),
+		WithComponent("http", intake, DependsOn("mysql"), InPhase(PhaseStopIntake)),
+		WithComponent("worker", rec.app("worker", nil), DependsOn("http")),
+		WithSignal(signalCh),
+		WithShutdownPhases(ShutdownPhases{PreStopDelay: preStop}),
+		WithHealth(registry),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	go func() {
+		for registry.Ready(context.Background()).Status != health.StatusUp {
+			time.Sleep(time.Millisecond)
+		}
+		signalAt = time.Now()
+		signalCh <- syscall.SIGTERM
+	}()
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// worker depends on http but it is drained after http stops taking new work
+	want := []string{"http", "worker", "mysql"}
+	if !reflect.DeepEqual(rec.stops, want) {
+		t.Errorf("shutdown order = %v, want %v", rec.stops, want)
+	}
+}
+
+func Test_Instance_ShutdownPhaseTimeout(t *testing.T) {
+	t.Parallel()
+
+	var closed bool
+	drain := &mockApp{}
+	signalCh := make(chan os.Signal, 1)
+	signalCh <- syscall.SIGTERM
+
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("worker", drain),
+		WithComponent("mysql", &mockApp{shutdownFunc: func() error {
+			closed = true
+			return nil
+		}}, InPhase(PhaseCloseResources)),
+		WithSignal(signalCh),
+		WithGracefulShutdown(time.Second),
+		WithShutdownPhases(ShutdownPhases{Drain: time.Millisecond * 10}),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	drain.shutdownFunc = func() error {
+		time.Sleep(time.Millisecond * 20)
+		return context.DeadlineExceeded
+	}
+
+	err := instance.Run(context.Background())
+	if !errors.Is(err, ErrShutdownTimeout) {
+		t.Errorf("Run() error = %v, want %v", err, ErrShutdownTimeout)
+	}
+	if !closed {
+		t.Error("close-resources phase is skipped after drain timed out")
+	}
+}
+
+func Test_Instance_StartContextCanceledInPhase(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mysqlCtx  = make(chan context.Context, 1)
+		drainSeen atomic.Bool
+		signalCh  = make(chan os.Signal, 1)
+	)
+	mysql := &ctxApp{ctxCh: mysqlCtx}
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("mysql", mysql, InPhase(PhaseCloseResources)),
+		WithComponent("worker", &mockApp{shutdownFunc: func() error {
+			// in-flight work of the worker still uses mysql
+			if err := (<-mysqlCtx).Err(); err != nil {
+				t.Errorf("mysql Start ctx error = %v while draining, want nil", err)
+			}
+			drainSeen.Store(true)
+			return nil
+		}}, DependsOn("mysql")),
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	instance.OnStarted(func(Event) { signalCh <- syscall.SIGTERM })
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !drainSeen.Load() {
+		t.Fatal("worker is not shut down")
+	}
+	if mysql.ctx.Err() == nil {
+		t.Error("mysql Start ctx is not canceled after its shutdown phase")
+	}
+}
+
+// ctxApp is a mockApp which hands the context of Start out
+type ctxApp struct {
+	mockApp
+	ctx   context.Context
+	ctxCh chan context.Context
+}
+
+func (a *ctxApp) Start(ctx context.Context) {
+	a.ctx = ctx
+	a.ctxCh <- ctx
+}
+
+func Test_Instance_WaitBackgroundTasks(t *testing.T) {
+	t.Parallel()
+
+	var done atomic.Bool
+	tasks := gctx.NewTasks()
+	signalCh := make(chan os.Signal, 1)
+
+	instance := NewInstance(context.Background(), nil,
+		WithComponent("http", &mockApp{shutdownFunc: func() error {
+			// a handler kicks off follow-up work before the server stops
+			return tasks.Go(context.Background(), func(ctx context.Context) error {
+				time.Sleep(time.Millisecond * 20)
+				done.Store(true)
+				return nil
+			})
+		}}),
+		WithComponent("mysql", &mockApp{shutdownFunc: func() error {
+			if !done.Load() {
+				t.Error("resources closed before the background tasks finished")
+			}
+			return nil
+		}}, InPhase(PhaseCloseResources)),
+		WithTasks(tasks),
+		WithSignal(signalCh),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	signalCh <- syscall.SIGTERM
+
+	if err := instance.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+// not parallel, the other tests wait for the DefaultTasks too
+func Test_Instance_DefaultTasksOutliveShutdown(t *testing.T) {
+	// two Instances in a row share the DefaultTasks
+	for range 2 {
+		signalCh := make(chan os.Signal, 1)
+		instance := NewInstance(context.Background(), &mockApp{},
+			WithSignal(signalCh),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		)
+		signalCh <- syscall.SIGTERM
+		if err := instance.Run(context.Background()); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	err := gctx.Go(context.Background(), func(context.Context) error {
+		close(done)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("gctx.Go() after shutdown error = %v, want nil", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("background task did not run")
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+type RestartPolicy int
+
+const (
+	// RestartNever shuts the instance down when a component panics, this is the default policy
+	RestartNever RestartPolicy = iota
+	// RestartOnPanic restarts a component whose Start panics
+	RestartOnPanic
+	// RestartAlways restarts a component whenever its Start returns or panics before shutdown
+	RestartAlways
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartOnPanic:
+		return "on-panic"
+	case RestartAlways:
+		return "always"
+	default:
+		return "never"
+	}
+}
+
+// Supervision controls how a component is restarted.
+// The delay between restarts starts at InitialBackoff and doubles up to MaxBackoff.
+// When a component restarts more than MaxRestarts times within Window,
+// the restart budget is exhausted and the whole instance is shut down.
+type Supervision struct {
+	Policy         RestartPolicy
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxRestarts    int
+	Window         time.Duration
+}
+
+func (s Supervision) withDefaults() Supervision {
+	if s.InitialBackoff <= 0 {
+		s.InitialBackoff = time.Millisecond * 100
+	}
+	if s.MaxBackoff < s.InitialBackoff {
+		s.MaxBackoff = max(time.Second*30, s.InitialBackoff)
+	}
+	if s.MaxRestarts <= 0 {
+		s.MaxRestarts = 5
+	}
+	if s.Window <= 0 {
+		s.Window = time.Minute
+	}
+
+	return s
+}
+
+func (s Supervision) shouldRestart(panicked bool) bool {
+	switch s.Policy {
+	case RestartAlways:
+		return true
+	case RestartOnPanic:
+		return panicked
+	default:
+		return false
+	}
+}
+
+// supervise runs the component and restarts it according to its Supervision
+// until the instance is shutting down or the restart budget is exhausted
+func (i *Instance) supervise(c *component) {
+	s := i.supervision
+	if c.supervision != nil {
+		s = *c.supervision
+	}
+	s = s.withDefaults()
+
+	var restarts []time.Time
+	backoff := s.InitialBackoff
+	for {
+		panicErr := i.runComponent(c)
+		if i.baseCtx.Err() != nil {
+			return
+		}
+		if !s.shouldRestart(panicErr != nil) {
+			if panicErr != nil {
+				i.cancelFunc(&ComponentError{Name: c.name, Err: panicErr})
+			}
+			return
+		}
+
+		// only count the restarts within the window
+		now := time.Now()
+		recent := restarts[:0]
+		for _, t := range restarts {
+			if now.Sub(t) < s.Window {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) == 0 {
+			backoff = s.InitialBackoff
+		}
+		restarts = append(recent, now)
+
+		if len(restarts) > s.MaxRestarts {
+			i.logger.Error("restart budget exhausted, shutting down",
+				"component", c.name, "restarts", s.MaxRestarts, "window", s.Window.String())
+			i.cancelFunc(&ComponentError{
+				Name: c.name,
+				Err:  fmt.Errorf("%w: restart budget exhausted", ErrComponentFailed),
+			})
+			return
+		}
+
+		i.logger.Warn("restarting component", "component", c.name, "policy", s.Policy.String(),
+			"attempt", len(restarts), "backoff", backoff.String())
+		select {
+		case <-time.After(backoff):
+		case <-i.baseCtx.Done():
+			return
+		}
+		backoff = min(backoff*2, s.MaxBackoff)
+	}
+}
+
+// runComponent calls Start and returns an error if it panicked
+func (i *Instance) runComponent(c *component) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			i.logger.Error("recover", "reason", r, "component", c.name)
+			err = fmt.Errorf("%w: panic: %v", ErrComponentFailed, r)
+		}
+	}()
+	c.startOnce.Do(func() { close(c.started) })
+	c.app.Start(c.ctx)
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Instance_Supervision(t *testing.T) {
+	testCases := []struct {
+		name        string
+		supervision Supervision
+		// panicUntil is the number of starts that panic, the next start cancels the instance
+		panicUntil int32
+		wantStarts int32
+		wantLog    string
+	}{
+		{
+			name:        "test restart on panic then stop on cancel",
+			supervision: Supervision{Policy: RestartOnPanic, InitialBackoff: time.Millisecond, MaxRestarts: 3},
+			panicUntil:  2,
+			wantStarts:  3,
+			wantLog:     "msg=\"restarting component\" component=worker policy=on-panic attempt=2",
+		},
+		{
+			name:        "test escalate when restart budget is exhausted",
+			supervision: Supervision{Policy: RestartOnPanic, InitialBackoff: time.Millisecond, MaxRestarts: 2},
+			panicUntil:  100,
+			wantStarts:  3,
+			wantLog:     "msg=\"restart budget exhausted, shutting down\" component=worker restarts=2",
+		},
+		{
+			name:        "test never restart",
+			supervision: Supervision{Policy: RestartNever},
+			panicUntil:  100,
+			wantStarts:  1,
+			wantLog:     "msg=recover reason=boom component=worker",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				starts    atomic.Int32
+				bufLogger = bytes.NewBuffer(nil)
+			)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			app := &mockApp{
+				startFunc: func() {
+					if starts.Add(1) <= tc.panicUntil {
+						panic("boom")
+					}
+					cancel()
+				},
+			}
+
+			instance := NewInstance(ctx, nil,
+				WithComponent("worker", app),
+				WithSupervision(tc.supervision),
+				WithLogger(slog.New(slog.NewTextHandler(bufLogger, nil))),
+			)
+			instance.Bootstrap()
+
+			if got := starts.Load(); got != tc.wantStarts {
+				t.Errorf("starts = %d, want %d", got, tc.wantStarts)
+			}
+			if !strings.Contains(bufLogger.String(), tc.wantLog) {
+				t.Errorf("want log: %s, got: %s", tc.wantLog, bufLogger.String())
+			}
+		})
+	}
+}
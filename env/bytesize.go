@@ -0,0 +1,86 @@
+package env
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes parsed from "64MB", "1.5GiB" or "512".
+// KB, MB, GB and TB are powers of 1000, KiB, MiB, GiB and TiB are powers of 1024.
+// Units are case insensitive.
+type ByteSize int64
+
+const (
+	Byte ByteSize = 1
+	KB            = 1000 * Byte
+	MB            = 1000 * KB
+	GB            = 1000 * MB
+	TB            = 1000 * GB
+	KiB           = 1024 * Byte
+	MiB           = 1024 * KiB
+	GiB           = 1024 * MiB
+	TiB           = 1024 * GiB
+)
+
+var byteUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+}
+
+// ParseByteSize parses s, e.g. "64MB", see ByteSize
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	idx := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if idx < 0 {
+		idx = len(s)
+	}
+
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[idx:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, s[idx:])
+	}
+	n, err := strconv.ParseFloat(s[:idx], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	size := n * float64(unit)
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: overflows int64", s)
+	}
+
+	return ByteSize(size), nil
+}
+
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// String returns the size in the largest binary unit dividing it, e.g. 64MiB
+func (b ByteSize) String() string {
+	for _, unit := range []struct {
+		size ByteSize
+		name string
+	}{{TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"}} {
+		if b != 0 && b%unit.size == 0 {
+			return fmt.Sprintf("%d%s", b/unit.size, unit.name)
+		}
+	}
+	return fmt.Sprintf("%dB", b)
+}
@@ -0,0 +1,195 @@
+package env
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// std is the global Config used by the package functions
+var std = &Config{p: &immutable}
+
+// Config is a set of values read from config files and environment variables.
+// Use New to build one for a component or a test, Default for the global one
+// and Namespace for a view of the keys under a prefix.
+type Config struct {
+	p *atomic.Pointer[config]
+	// the prefix of the keys of a Namespace view, empty or ending with "."
+	ns string
+}
+
+// New returns a Config built from opts, it is independent of Init and Reload
+func New(opts ...option) *Config {
+	p := new(atomic.Pointer[config])
+	p.Store(automaticEnv(opts...))
+	return &Config{p: p}
+}
+
+// Default returns the global Config set up by Init, it follows Reload
+func Default() *Config {
+	return std
+}
+
+// Err returns the errors occur when the Config is built, e.g. the config file cannot be read
+func (c *Config) Err() error {
+	return c.load().err
+}
+
+// Namespace returns a view of the keys of c under name, e.g. Namespace("payments").GetString("http.server.addr")
+// reads payments.http.server.addr. Namespaces can be nested.
+func (c *Config) Namespace(name string) *Config {
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return c
+	}
+	return &Config{p: c.p, ns: c.ns + name + "."}
+}
+
+// Namespace returns a view of the keys of the global config under name, see Config.Namespace
+func Namespace(name string) *Config {
+	return std.Namespace(name)
+}
+
+func (c *Config) load() *config {
+	return c.p.Load()
+}
+
+// key returns the full key of key in the namespace of c
+func (c *Config) key(key string) string {
+	return c.ns + key
+}
+
+// IsSet reports whether key has a value
+func (c *Config) IsSet(key string) bool {
+	return c.load().IsSet(c.key(key))
+}
+
+// GetString returns the value of key with its references resolved, see the GetString function
+func (c *Config) GetString(key string) string {
+	return c.load().GetString(c.key(key))
+}
+
+func (c *Config) GetStringSlice(key string) []string {
+	return c.load().GetStringSlice(c.key(key))
+}
+
+func (c *Config) GetInt(key string) int {
+	return c.load().GetInt(c.key(key))
+}
+
+func (c *Config) GetIntSlice(key string) []int {
+	return c.load().GetIntSlice(c.key(key))
+}
+
+func (c *Config) GetUint(key string) uint {
+	return c.load().GetUint(c.key(key))
+}
+
+func (c *Config) GetBool(key string) bool {
+	return c.load().GetBool(c.key(key))
+}
+
+func (c *Config) GetDuration(key string) time.Duration {
+	return c.load().GetDuration(c.key(key))
+}
+
+func (c *Config) GetFloat64(key string) float64 {
+	return c.load().GetFloat64(c.key(key))
+}
+
+// GetSecret returns the value of key as a Secret and marks the key as a secret
+func (c *Config) GetSecret(key string) Secret {
+	key = c.key(key)
+	MarkSecret(key)
+	return Secret(c.load().GetString(key))
+}
+
+// Source returns the layer supplying the value of key, see the Source function
+func (c *Config) Source(key string) (Layer, bool) {
+	return c.load().source(c.key(key))
+}
+
+func (c *Config) MustString(key string) string {
+	key = c.key(key)
+	cnf := c.load()
+	if cnf.IsSet(key) {
+		s, err := cnf.resolveKey(key)
+		if err != nil {
+			panic(err.Error())
+		}
+		return s
+	}
+	panic(required(key))
+}
+
+func (c *Config) MustStringSlice(key string) []string {
+	key = c.key(key)
+	if cnf := c.load(); cnf.IsSet(key) {
+		return cnf.GetStringSlice(key)
+	}
+	panic(required(key))
+}
+
+func (c *Config) MustInt(key string) int {
+	key = c.key(key)
+	if cnf := c.load(); cnf.IsSet(key) {
+		return cnf.GetInt(key)
+	}
+	panic(required(key))
+}
+
+func (c *Config) MustIntSlice(key string) []int {
+	key = c.key(key)
+	if cnf := c.load(); cnf.IsSet(key) {
+		return cnf.GetIntSlice(key)
+	}
+	panic(required(key))
+}
+
+func (c *Config) MustUint(key string) uint {
+	key = c.key(key)
+	if cnf := c.load(); cnf.IsSet(key) {
+		return cnf.GetUint(key)
+	}
+	panic(required(key))
+}
+
+func (c *Config) MustDuration(key string) time.Duration {
+	key = c.key(key)
+	if cnf := c.load(); cnf.IsSet(key) {
+		return cnf.GetDuration(key)
+	}
+	panic(required(key))
+}
+
+func (c *Config) MustFloat64(key string) float64 {
+	key = c.key(key)
+	if cnf := c.load(); cnf.IsSet(key) {
+		return cnf.GetFloat64(key)
+	}
+	panic(required(key))
+}
+
+// With returns a copy of c with values overriding its keys in the namespace of c, values take precedence
+// over files and environment variables. c is not modified.
+func (c *Config) With(values map[string]any) *Config {
+	full := make(map[string]any, len(values))
+	for key, value := range values {
+		full[c.key(key)] = value
+	}
+
+	cnf := c.load()
+	opts := append(cnf.opts[:len(cnf.opts):len(cnf.opts)], WithValues(full))
+	cfg := New(opts...)
+	cfg.ns = c.ns
+	return cfg
+}
+
+// Override replaces the global config with Default().With(values) until restore is called,
+// a Reload in the meantime drops the values. Use envtest.Override in tests.
+func Override(values map[string]any) (restore func()) {
+	prev := immutable.Swap(std.With(values).load())
+	return func() {
+		immutable.Store(prev)
+	}
+}
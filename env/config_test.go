@@ -0,0 +1,94 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "http:\n  server:\n    addr: :8080\n    timeout: 5s\n")
+
+	cfg := New(WithFile(file))
+	if err := cfg.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if got := cfg.GetString("http.server.addr"); got != ":8080" {
+		t.Errorf("GetString(http.server.addr) = %q, want :8080", got)
+	}
+
+	override := cfg.With(map[string]any{"http.server.addr": ":9090"})
+	if got := override.GetString("http.server.addr"); got != ":9090" {
+		t.Errorf("With().GetString(http.server.addr) = %q, want :9090", got)
+	}
+	if got := override.MustDuration("http.server.timeout"); got != 5*time.Second {
+		t.Errorf("With().MustDuration(http.server.timeout) = %s, want 5s", got)
+	}
+	// the original is not modified
+	if got := cfg.GetString("http.server.addr"); got != ":8080" {
+		t.Errorf("GetString(http.server.addr) after With = %q, want :8080", got)
+	}
+
+	if New(WithFile(filepath.Join(t.TempDir(), "missing.yaml"))).Err() == nil {
+		t.Error("Err() = nil, want error for a missing file")
+	}
+}
+
+func TestOverride(t *testing.T) {
+	restore := Override(map[string]any{"log.level": "debug"})
+	if got := GetString("log.level"); got != "debug" {
+		t.Errorf("GetString(log.level) = %q, want debug", got)
+	}
+	restore()
+	if Default().IsSet("log.level") {
+		t.Error("log.level is set after restore")
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+
+	cfg := New(WithValues(map[string]any{
+		"payments.http.server.addr": ":9090",
+		"payments.http.client.size": "1MiB",
+		"http.server.addr":          ":8080",
+	}))
+
+	payments := cfg.Namespace("payments")
+	if got := payments.GetString("http.server.addr"); got != ":9090" {
+		t.Errorf("GetString(http.server.addr) = %q, want :9090", got)
+	}
+	if got := payments.Namespace("http.client").GetByteSize("size"); got != MiB {
+		t.Errorf("GetByteSize(size) = %s, want 1MiB", got)
+	}
+	if got, err := GetFrom[string](payments.Namespace("http"), "server.addr"); err != nil || got != ":9090" {
+		t.Errorf("GetFrom(server.addr) = %q, %v, want :9090", got, err)
+	}
+	if _, err := GetFrom[string](payments, "log.level"); err == nil || err.Error() != "PAYMENTS_LOG_LEVEL: key is not set" {
+		t.Errorf("GetFrom(log.level) error = %v, want the full key", err)
+	}
+
+	override := payments.With(map[string]any{"http.server.addr": ":7070"})
+	if got := override.GetString("http.server.addr"); got != ":7070" {
+		t.Errorf("With().GetString(http.server.addr) = %q, want :7070", got)
+	}
+	if got := cfg.GetString("http.server.addr"); got != ":8080" {
+		t.Errorf("GetString(http.server.addr) = %q, want :8080 outside of the namespace", got)
+	}
+}
+
+func TestWithEnvPrefix(t *testing.T) {
+	t.Setenv("PAYMENTS_HTTP_SERVER_ADDR", ":9090")
+	t.Setenv("HTTP_SERVER_ADDR", ":8080")
+
+	cfg := New(WithEnvPrefix("payments"))
+	if got := cfg.GetString("http.server.addr"); got != ":9090" {
+		t.Errorf("GetString(http.server.addr) = %q, want :9090", got)
+	}
+	if layer, _ := cfg.Source("http.server.addr"); layer.Name != "PAYMENTS_HTTP_SERVER_ADDR" {
+		t.Errorf("Source(http.server.addr) = %v, want PAYMENTS_HTTP_SERVER_ADDR", layer)
+	}
+}
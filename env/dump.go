@@ -0,0 +1,149 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formats of Dump
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+var errUnsupportedFormat = errors.New("unsupported format")
+
+// keys bound by Load, they are dumped even when only an environment variable sets them
+var boundKeys sync.Map
+
+// bindKey records key for the dump
+func bindKey(key string) {
+	boundKeys.Store(normalizeKey(key), struct{}{})
+}
+
+// Entry is the effective value of a key and the layer which supplied it
+type Entry struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  any    `json:"value" yaml:"value"`
+	Type   string `json:"type" yaml:"type"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// Effective returns the effective value of every key, sorted by key.
+// Besides the keys of the files and providers, it returns the keys set by an environment variable
+// which are bound by Load, marked by MarkSecret or, with an env prefix, the variables with the prefix.
+// String values are resolved, secrets are redacted.
+func Effective() []Entry {
+	return current().effective()
+}
+
+// Explain returns the effective value of key, ok is false if key is not set
+func Explain(key string) (Entry, bool) {
+	c := current()
+	if !c.IsSet(key) {
+		return Entry{}, false
+	}
+	return c.entry(normalizeKey(key)), true
+}
+
+// Dump writes the effective config to w in format, FormatYAML or FormatJSON,
+// so the configs of two instances can be diffed
+func Dump(w io.Writer, format string) error {
+	entries := Effective()
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(entries); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	default:
+		return fmt.Errorf("%w: %q", errUnsupportedFormat, format)
+	}
+}
+
+func (c *config) effective() []Entry {
+	keys := append(c.AllKeys(), c.envKeys()...)
+	slices.Sort(keys)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, c.entry(key))
+	}
+	return entries
+}
+
+// envKeys returns the keys which viper does not list because only an environment variable sets them
+func (c *config) envKeys() []string {
+	var keys []string
+	// the variables read by the known keys
+	covered := make(map[string]struct{})
+	for _, key := range c.AllKeys() {
+		covered[c.envVarName(key)] = struct{}{}
+	}
+	add := func(key string) {
+		name := c.envVarName(key)
+		if _, ok := covered[name]; ok {
+			return
+		}
+		covered[name] = struct{}{}
+		// empty variables are ignored like viper does
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	collect := func(key, _ any) bool {
+		add(key.(string))
+		return true
+	}
+	boundKeys.Range(collect)
+	secretKeys.Range(collect)
+
+	// without a prefix, every variable of the host would be a key
+	if prefix := c.GetEnvPrefix(); prefix != "" {
+		prefix = envKey(prefix) + "_"
+		for _, kv := range os.Environ() {
+			name, _, _ := strings.Cut(kv, "=")
+			if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+				add(normalizeKey(strings.ReplaceAll(rest, "_", ".")))
+			}
+		}
+	}
+
+	return keys
+}
+
+func (c *config) entry(key string) Entry {
+	value := c.Get(key)
+	entry := Entry{Key: key, Type: fmt.Sprintf("%T", value), Source: "default"}
+	if layer, ok := c.source(key); ok {
+		entry.Source = layer.String()
+	}
+
+	if _, ok := value.(string); ok {
+		// an unresolved reference is dumped as is
+		if s, err := c.resolveKey(key); err != nil {
+			slog.Error(err.Error())
+		} else {
+			value = s
+		}
+	}
+	entry.Value = Redact(key, value)
+
+	return entry
+}
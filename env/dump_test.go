@@ -0,0 +1,111 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDump(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	secretFile := filepath.Join(dir, "password")
+	writeFile(t, secretFile, "passwd")
+	writeFile(t, file, "mysql:\n  password: file://"+secretFile+"\n  port: 3306\n  user: root\n")
+	t.Setenv("MYSQL_USER", "admin")
+	useConfigFile(t, file)
+
+	want := []Entry{
+		{Key: "mysql.password", Value: redacted, Type: "string", Source: "file:" + file},
+		{Key: "mysql.port", Value: float64(3306), Type: "int", Source: "file:" + file},
+		{Key: "mysql.user", Value: "admin", Type: "string", Source: "env:MYSQL_USER"},
+	}
+
+	var out bytes.Buffer
+	if err := Dump(&out, FormatJSON); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	var got []Entry
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal json error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dump(json) = %+v, want %+v", got, want)
+	}
+	if strings.Contains(out.String(), "passwd") {
+		t.Errorf("Dump(json) = %s, leaks the secret", out.String())
+	}
+
+	out.Reset()
+	if err := Dump(&out, FormatYAML); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	got = nil
+	if err := yaml.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal yaml error = %v", err)
+	}
+	want[1].Value = 3306
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dump(yaml) = %+v, want %+v", got, want)
+	}
+
+	if err := Dump(&out, "toml"); err == nil {
+		t.Error("Dump(toml) error = nil, want unsupported format")
+	}
+}
+
+func TestEffective_EnvOnly(t *testing.T) {
+	type serverConfig struct {
+		Addr string `env:"http.server.addr"`
+	}
+
+	// no file key, the key is known because Load binds it
+	t.Setenv("HTTP_SERVER_ADDR", ":9090")
+	useOptions(t)
+	if _, err := Load[serverConfig](); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []Entry{{Key: "http.server.addr", Value: ":9090", Type: "string", Source: "env:HTTP_SERVER_ADDR"}}
+	if got := Effective(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Effective() = %+v, want %+v", got, want)
+	}
+	if entry, ok := Explain("http.server.addr"); !ok || entry != want[0] {
+		t.Errorf("Explain(http.server.addr) = %+v, %v, want %+v, true", entry, ok, want[0])
+	}
+}
+
+func TestEffective_EnvPrefix(t *testing.T) {
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("APP_MYSQL_MAX_CONNS", "10")
+	useOptions(t, WithEnvPrefix("APP"))
+	// the marked key wins over the dotted form of the variable, mysql.max.conns
+	MarkSecret("mysql.max_conns")
+
+	want := []Entry{
+		{Key: "log.level", Value: "debug", Type: "string", Source: "env:APP_LOG_LEVEL"},
+		{Key: "mysql.max_conns", Value: redacted, Type: "string", Source: "env:APP_MYSQL_MAX_CONNS"},
+	}
+	if got := Effective(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Effective() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "log:\n  level: info\n")
+	useConfigFile(t, file)
+
+	entry, ok := Explain("log.level")
+	if want := (Entry{Key: "log.level", Value: "info", Type: "string", Source: "file:" + file}); !ok || entry != want {
+		t.Errorf("Explain(log.level) = %+v, %v, want %+v, true", entry, ok, want)
+	}
+	if _, ok := Explain("log.format"); ok {
+		t.Error("Explain(log.format) ok = true, want false")
+	}
+}
@@ -0,0 +1,55 @@
+// Package envtest overrides config keys in tests
+package envtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ngoctd314/common/env"
+)
+
+var (
+	mu sync.Mutex
+	// the tests whose overrides are active, a subtest shares the global config of its parent
+	owners []testing.TB
+)
+
+// New returns a Config with values overriding the global config, it is scoped to the caller
+// so it is safe for parallel tests. Pass it to the component under test, e.g. ghttp.WithServerConfig.
+func New(values map[string]any) *env.Config {
+	return env.Default().With(values)
+}
+
+// Override sets values on the global config for the lifetime of t.
+// The global config is shared by the whole process, so Override must not be used by parallel tests:
+// t fails if the override of a test which is not t or one of its parents is still active.
+// A parallel test reading the global config without Override is not detected, use New there.
+func Override(t testing.TB, values map[string]any) {
+	t.Helper()
+
+	mu.Lock()
+	for _, owner := range owners {
+		if owner != t && !strings.HasPrefix(t.Name(), owner.Name()+"/") {
+			mu.Unlock()
+			t.Fatalf("envtest.Override is used by %s and %s at the same time, run them sequentially or use envtest.New", owner.Name(), t.Name())
+			return
+		}
+	}
+	owners = append(owners, t)
+	mu.Unlock()
+
+	restore := env.Override(values)
+	t.Cleanup(func() {
+		restore()
+
+		mu.Lock()
+		defer mu.Unlock()
+		for idx := len(owners) - 1; idx >= 0; idx-- {
+			if owners[idx] == t {
+				owners = append(owners[:idx], owners[idx+1:]...)
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package envtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ngoctd314/common/env"
+)
+
+func TestOverride(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		Override(t, map[string]any{"log.level": "debug"})
+		if got := env.GetString("log.level"); got != "debug" {
+			t.Errorf("GetString(log.level) = %q, want debug", got)
+		}
+	})
+
+	if env.Default().IsSet("log.level") {
+		t.Error("log.level is set after the test")
+	}
+}
+
+// fakeTB records Fatalf instead of stopping the goroutine
+type fakeTB struct {
+	testing.TB
+	name    string
+	fatal   string
+	cleanup []func()
+}
+
+func (f *fakeTB) Helper()           {}
+func (f *fakeTB) Name() string      { return f.name }
+func (f *fakeTB) Cleanup(fn func()) { f.cleanup = append(f.cleanup, fn) }
+func (f *fakeTB) Fatalf(format string, args ...any) {
+	f.fatal = fmt.Sprintf(format, args...)
+}
+
+func (f *fakeTB) done() {
+	for idx := len(f.cleanup) - 1; idx >= 0; idx-- {
+		f.cleanup[idx]()
+	}
+}
+
+func TestOverride_Concurrent(t *testing.T) {
+	a := &fakeTB{name: "TestA"}
+	Override(a, map[string]any{"log.level": "debug"})
+	defer a.done()
+
+	// a subtest of an overriding test shares its config
+	sub := &fakeTB{name: "TestA/sub"}
+	Override(sub, map[string]any{"log.format": "json"})
+	sub.done()
+	if sub.fatal != "" {
+		t.Errorf("Override() in a subtest failed: %s", sub.fatal)
+	}
+
+	b := &fakeTB{name: "TestB"}
+	Override(b, map[string]any{"log.level": "info"})
+	b.done()
+	if !strings.Contains(b.fatal, "TestA and TestB") {
+		t.Errorf("Override() fatal = %q, want the overlapping tests", b.fatal)
+	}
+	if got := env.GetString("log.level"); got != "debug" {
+		t.Errorf("GetString(log.level) = %q, want debug kept by TestA", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	cfg := New(map[string]any{"log.level": "debug"})
+	if got := cfg.GetString("log.level"); got != "debug" {
+		t.Errorf("GetString(log.level) = %q, want debug", got)
+	}
+	if env.Default().IsSet("log.level") {
+		t.Error("New modified the global config")
+	}
+}
@@ -4,60 +4,42 @@ import (
 	"time"
 )
 
+// GetString returns the value of key with its references resolved:
+//   - file:///run/secrets/db_password is replaced by the content of the file, the key becomes a secret
+//   - base64:cGFzc3dk is replaced by the decoded value
+//   - ${OTHER_KEY} or ${other.key} is replaced by the value of the other key, $$ escapes $
+//
+// If a reference cannot be resolved, the error is logged and an empty string is returned,
+// MustString panics with the error instead. E.g. with the password mounted as a secret:
+//
+//	mysql:
+//	  dev:
+//	    password: file:///run/secrets/mysql_dev_password
+//	    dsn: user:${mysql.dev.password}@tcp(ip:port)/dbName?parseTime=True
 func GetString(key string) string {
-	return immutable.GetString(key)
+	return std.GetString(key)
 }
 
 func GetStringSlice(key string) []string {
-	return immutable.GetStringSlice(key)
+	return std.GetStringSlice(key)
 }
 
 func GetInt(key string) int {
-	return immutable.GetInt(key)
+	return std.GetInt(key)
 }
 
 func GetIntSlice(key string) []int {
-	return immutable.GetIntSlice(key)
+	return std.GetIntSlice(key)
 }
 
 func GetUint(key string) uint {
-	return immutable.GetUint(key)
+	return std.GetUint(key)
 }
 
 func GetDuration(key string) time.Duration {
-	return immutable.GetDuration(key)
+	return std.GetDuration(key)
 }
 
 func GetFloat64(key string) float64 {
-	return immutable.GetFloat64(key)
-}
-
-type sentinelDefaultType interface {
-	~string | []string | ~int | []int | ~bool | ~uint | ~float64 | time.Duration
-}
-
-// GetWithDefault get the value from the environment if it exists, otherwise return the default value.
-func GetWithDefault[T sentinelDefaultType](key string, defaultValue T) T {
-	if immutable.IsSet(key) {
-		switch any(defaultValue).(type) {
-		case string:
-			return any(immutable.GetString(key)).(T)
-		case []string:
-			return any(immutable.GetStringSlice(key)).(T)
-		case int:
-			return any(immutable.GetInt(key)).(T)
-		case uint:
-			return any(immutable.GetUint(key)).(T)
-		case []int:
-			return any(immutable.GetIntSlice(key)).(T)
-		case bool:
-			return any(immutable.GetBool(key)).(T)
-		case float64:
-			return any(immutable.GetFloat64(key)).(T)
-		case time.Duration:
-			return any(immutable.GetDuration(key)).(T)
-		}
-	}
-
-	return defaultValue
+	return std.GetFloat64(key)
 }
@@ -3,29 +3,50 @@ package env
 import (
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/spf13/viper"
 )
 
 var (
-	// immutable: global config object
-	immutable *config
+	// immutable: global config object, it is only replaced as a whole by Reload
+	immutable atomic.Pointer[config]
 	// sentinel to make sure that Init func is called only once
 	initOnce sync.Once
+	// options passed to Init, Reload applies them again
+	initOpts []option
 )
 
 // wrapper Viper as our config
 type config struct {
 	*viper.Viper
+	// errors occur when applying options, e.g. the config file cannot be read
+	err error
+	// config files to read, in merge order
+	files []string
+	// config files read, in merge order
+	layers []fileLayer
+	// providers read, in option order
+	providers []providerLayer
+	// keys set by WithValues
+	values map[string]struct{}
+	mode   string
+	// options the config is built from
+	opts []option
 }
 
 func init() {
-	immutable = automaticEnv()
+	immutable.Store(automaticEnv())
+}
+
+func current() *config {
+	return immutable.Load()
 }
 
 func automaticEnv(opts ...option) *config {
 	cnf := &config{
-		viper.New(),
+		Viper: viper.New(),
+		opts:  opts,
 	}
 	for _, opt := range opts {
 		opt(cnf)
@@ -47,7 +68,8 @@ func automaticEnv(opts ...option) *config {
 func Init(opts ...option) {
 	// it is confused when Init config twice or more
 	initOnce.Do(func() {
-		immutable = automaticEnv(opts...)
+		initOpts = opts
+		immutable.Store(automaticEnv(opts...))
 	})
 }
 
@@ -0,0 +1,136 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// kinds of Layer, see also LayerProvider
+const (
+	LayerFile = "file"
+	LayerEnv  = "env"
+	// values set by WithValues, Config.With or Override, they win over every other layer
+	LayerOverride = "override"
+)
+
+// Layer is a source of config values, Name is the file path, the environment variable name
+// or the provider name
+type Layer struct {
+	Kind string
+	Name string
+}
+
+func (l Layer) String() string {
+	return fmt.Sprintf("%s:%s", l.Kind, l.Name)
+}
+
+// fileLayer is a config file merged into the config and the keys it supplies
+type fileLayer struct {
+	file string
+	keys map[string]struct{}
+}
+
+// WithLayeredFiles reads baseFile then merges the overlay of mode over it,
+// e.g. config.yaml then config_prod.yaml for ProdMode. The overlay is optional.
+// Precedence, from the lowest: base file, overlay file, environment variables.
+func WithLayeredFiles(baseFile, mode string) option {
+	return func(c *config) {
+		c.mode = mode
+		WithFile(baseFile)(c)
+		if strings.TrimSpace(mode) == "" {
+			return
+		}
+
+		overlay := overlayFile(strings.TrimSpace(baseFile), mode)
+		c.files = append(c.files, overlay)
+		if _, err := os.Stat(overlay); errors.Is(err, os.ErrNotExist) {
+			slog.Info(fmt.Sprintf("no %s overlay, use %s only", mode, baseFile))
+			return
+		}
+		if err := c.mergeFile(overlay); err != nil {
+			slog.Warn(fmt.Sprintf("error occur when read config from file, err: '%v', config file: '%s'", err, overlay))
+			c.err = errors.Join(c.err, err)
+		}
+	}
+}
+
+// overlayFile returns the file of mode next to baseFile, config.yaml -> config_prod.yaml
+func overlayFile(baseFile, mode string) string {
+	ext := filepath.Ext(baseFile)
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(baseFile, ext), mode, ext)
+}
+
+// mergeFile reads file and merges it over the files already read
+func (c *config) mergeFile(file string) error {
+	layer := viper.New()
+	layer.SetConfigFile(file)
+	if err := layer.ReadInConfig(); err != nil {
+		return err
+	}
+	if err := c.MergeConfigMap(layer.AllSettings()); err != nil {
+		return err
+	}
+
+	keys := make(map[string]struct{})
+	for _, key := range layer.AllKeys() {
+		keys[key] = struct{}{}
+	}
+	c.layers = append(c.layers, fileLayer{file: file, keys: keys})
+
+	return nil
+}
+
+// Mode returns the run mode passed to WithLayeredFiles, e.g. ProdMode
+func Mode() string {
+	return current().mode
+}
+
+// Source returns the layer supplying the value of key: the values of WithValues first, then the environment,
+// the config files from the last merged to the first and the providers.
+// ok is false if key is not set.
+func Source(key string) (layer Layer, ok bool) {
+	return current().source(key)
+}
+
+func (c *config) source(key string) (Layer, bool) {
+	for valueKey := range c.values {
+		if hasKeyPrefix(normalizeKey(key), valueKey) {
+			return Layer{Kind: LayerOverride, Name: valueKey}, true
+		}
+	}
+
+	name := c.envVarName(key)
+	// empty variables are ignored like viper does
+	if v, ok := os.LookupEnv(name); ok && v != "" {
+		return Layer{Kind: LayerEnv, Name: name}, true
+	}
+
+	key = normalizeKey(key)
+	for idx := len(c.layers) - 1; idx >= 0; idx-- {
+		if _, ok := c.layers[idx].keys[key]; ok {
+			return Layer{Kind: LayerFile, Name: c.layers[idx].file}, true
+		}
+	}
+
+	for idx := len(c.providers) - 1; idx >= 0; idx-- {
+		if _, ok := c.providers[idx].keys[key]; ok {
+			return Layer{Kind: LayerProvider, Name: c.providers[idx].name}, true
+		}
+	}
+
+	return Layer{}, false
+}
+
+// envVarName returns the environment variable read for key, with the env prefix if any
+func (c *config) envVarName(key string) string {
+	if prefix := c.GetEnvPrefix(); prefix != "" {
+		return envKey(prefix + "_" + key)
+	}
+	return envKey(key)
+}
@@ -0,0 +1,116 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// useLayeredFiles makes the global config read the files as if Init(WithLayeredFiles(file, mode)) was called
+func useLayeredFiles(t *testing.T, file, mode string) {
+	t.Helper()
+	useOptions(t, WithLayeredFiles(file, mode))
+}
+
+func TestWithLayeredFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	overlay := filepath.Join(dir, "config_prod.yaml")
+	writeFile(t, base, "http:\n  server:\n    addr: :8080\n    timeout: 5s\nlog:\n  level: debug\n")
+	writeFile(t, overlay, "http:\n  server:\n    addr: :80\n")
+	t.Setenv("LOG_LEVEL", "warn")
+	useLayeredFiles(t, base, ProdMode)
+
+	if Mode() != ProdMode {
+		t.Errorf("Mode() = %q, want %q", Mode(), ProdMode)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+		layer Layer
+		ok    bool
+	}{
+		{key: "http.server.addr", value: ":80", layer: Layer{Kind: LayerFile, Name: overlay}, ok: true},
+		{key: "http.server.timeout", value: "5s", layer: Layer{Kind: LayerFile, Name: base}, ok: true},
+		{key: "log.level", value: "warn", layer: Layer{Kind: LayerEnv, Name: "LOG_LEVEL"}, ok: true},
+		{key: "http.client.timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := GetString(tt.key); got != tt.value {
+				t.Errorf("GetString(%q) = %q, want %q", tt.key, got, tt.value)
+			}
+			layer, ok := Source(tt.key)
+			if layer != tt.layer || ok != tt.ok {
+				t.Errorf("Source(%q) = %v, %v, want %v, %v", tt.key, layer, ok, tt.layer, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSource_Override(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "http:\n  server:\n    addr: :8080\nlog:\n  level: debug\n")
+	useConfigFile(t, file)
+
+	restore := Override(map[string]any{"http.server.addr": ":9090", "labels": map[string]any{"team": "core"}})
+	defer restore()
+
+	tests := []struct {
+		key   string
+		layer Layer
+	}{
+		{key: "http.server.addr", layer: Layer{Kind: LayerOverride, Name: "http.server.addr"}},
+		{key: "labels.team", layer: Layer{Kind: LayerOverride, Name: "labels"}},
+		{key: "log.level", layer: Layer{Kind: LayerFile, Name: file}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if layer, ok := Source(tt.key); !ok || layer != tt.layer {
+				t.Errorf("Source(%q) = %v, %v, want %v, true", tt.key, layer, ok, tt.layer)
+			}
+		})
+	}
+	if entry, ok := Explain("http.server.addr"); !ok || entry.Source != "override:http.server.addr" {
+		t.Errorf("Explain(http.server.addr) = %+v, %v, want the override source", entry, ok)
+	}
+}
+
+func TestWithLayeredFilesWithoutOverlay(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, base, "log:\n  level: debug\n")
+	useLayeredFiles(t, base, TestingMode)
+
+	if err := current().err; err != nil {
+		t.Errorf("config error = %v, want nil for a missing overlay", err)
+	}
+	if GetString("log.level") != "debug" {
+		t.Errorf("log.level = %q, want debug", GetString("log.level"))
+	}
+
+	// the overlay created later is merged on reload
+	writeFile(t, filepath.Join(filepath.Dir(base), "config_testing.yaml"), "log:\n  level: error\n")
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if GetString("log.level") != "error" {
+		t.Errorf("log.level = %q, want error", GetString("log.level"))
+	}
+}
+
+func TestOverlayFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		base, mode, want string
+	}{
+		{base: "config.yaml", mode: ProdMode, want: "config_prod.yaml"},
+		{base: "/etc/app/config.json", mode: DevMode, want: "/etc/app/config_dev.json"},
+		{base: "config", mode: DebugMode, want: "config_debug"},
+	}
+	for _, tt := range tests {
+		if got := overlayFile(tt.base, tt.mode); got != tt.want {
+			t.Errorf("overlayFile(%q, %q) = %q, want %q", tt.base, tt.mode, got, tt.want)
+		}
+	}
+}
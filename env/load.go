@@ -0,0 +1,365 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ngoctd314/common/gvalidator"
+	"github.com/spf13/cast"
+)
+
+var (
+	errInvalidConfig   = errors.New("invalid config")
+	errNotStruct       = errors.New("config type must be a struct")
+	errUnsupportedType = errors.New("unsupported type")
+	durationType       = reflect.TypeOf(time.Duration(0))
+	secretType         = reflect.TypeOf(Secret(""))
+	timeType           = reflect.TypeOf(time.Time{})
+	urlType            = reflect.TypeOf(url.URL{})
+	textUnmarshaler    = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Load binds the config values into a new T and validates it with gvalidator.
+// String values are resolved first, see GetString for the supported references.
+// Fields are bound by tags:
+//   - env:"http.server.addr" is the key of the value, options follow the key:
+//     env:"mysql.dev.dsn,required,secret"
+//   - required: the key must be set, unless there is a default
+//   - secret: the value is never printed in errors, dumps or logs, fields of type Secret are secrets too
+//   - default:"5s" is used when the key is not set
+//   - validate:"min=1" rules of gvalidator, run after binding
+//   - doc:"..." describes the key in the files generated by SampleYAML, JSONSchema and MarkdownTable
+//
+// A struct field tagged env:"http" prefixes the keys of its own fields with "http.",
+// an untagged struct field shares the prefix of its parent.
+// A slice set by a string, e.g. an environment variable, lists its items separated by whitespace
+// like GetStringSlice does: KAFKA_BROKERS="kafka-1:9092 kafka-2:9092".
+// The returned error lists every missing or invalid key in UPPER_SNAKE form.
+//
+//	type HTTPConfig struct {
+//		Addr    string        `env:"addr,required"`
+//		Timeout time.Duration `env:"timeout" default:"5s" validate:"min=1ms"`
+//	}
+//	type Config struct {
+//		HTTP HTTPConfig `env:"http.server"`
+//		DSN  string     `env:"mysql.dev.dsn,required,secret"`
+//	}
+//	cfg, err := env.Load[Config]()
+func Load[T any]() (T, error) {
+	return load[T](current())
+}
+
+func load[T any](c *config) (T, error) {
+	var cfg T
+
+	v := reflect.ValueOf(&cfg).Elem()
+	if v.Kind() != reflect.Struct {
+		return cfg, fmt.Errorf("%w, got %s", errNotStruct, v.Type())
+	}
+
+	fields := structFields(v, "", v.Type().Name())
+	var errs []error
+	for _, f := range fields {
+		bindKey(f.key)
+		raw, ok := c.Get(f.key), c.IsSet(f.key)
+		if !ok && f.hasDefault {
+			raw, ok = f.def, true
+		}
+		if !ok {
+			if f.required {
+				errs = append(errs, errors.New(required(f.key)))
+			}
+			continue
+		}
+
+		if s, isString := raw.(string); isString {
+			resolved, secret, err := c.resolve(s, 0)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", envKey(f.key), err))
+				continue
+			}
+			raw = resolved
+			f.secret = f.secret || secret
+		}
+		if f.secret {
+			MarkSecret(f.key)
+		}
+
+		if err := decode(raw, f.value); err != nil {
+			errs = append(errs, invalid(f, err))
+		}
+	}
+	if len(errs) > 0 {
+		return cfg, fmt.Errorf("%w: %w", errInvalidConfig, errors.Join(errs...))
+	}
+
+	if err := gvalidator.ValidateStruct(cfg); err != nil {
+		var vErrs validator.ValidationErrors
+		if !errors.As(err, &vErrs) {
+			return cfg, fmt.Errorf("%w: %w", errInvalidConfig, err)
+		}
+
+		byNamespace := make(map[string]field, len(fields))
+		for _, f := range fields {
+			byNamespace[f.namespace] = f
+		}
+		trans := gvalidator.GetTranslator("en")
+		for _, e := range vErrs {
+			f, ok := byNamespace[e.StructNamespace()]
+			if !ok {
+				errs = append(errs, errors.New(e.Translate(trans)))
+				continue
+			}
+			errs = append(errs, invalid(f, errors.New(e.Translate(trans))))
+		}
+
+		return cfg, fmt.Errorf("%w: %w", errInvalidConfig, errors.Join(errs...))
+	}
+
+	return cfg, nil
+}
+
+// field is a struct field bound to a config key
+type field struct {
+	key string
+	// the path of the field used by the validator, e.g. Config.HTTP.Addr
+	namespace  string
+	value      reflect.Value
+	def        string
+	hasDefault bool
+	required   bool
+	secret     bool
+	doc        string
+}
+
+func structFields(v reflect.Value, prefix, namespace string) []field {
+	var fields []field
+
+	t := v.Type()
+	for idx := range t.NumField() {
+		sf := t.Field(idx)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, hasTag := sf.Tag.Lookup("env")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" && name != "" {
+			key = prefix + "." + name
+		} else if name == "" {
+			key = prefix
+		}
+
+		fv := v.Field(idx)
+		ns := namespace + "." + sf.Name
+		if sf.Type.Kind() == reflect.Struct && !isValue(sf.Type) {
+			fields = append(fields, structFields(fv, key, ns)...)
+			continue
+		}
+		if !hasTag || name == "" {
+			continue
+		}
+
+		f := field{key: key, namespace: ns, value: fv, secret: sf.Type == secretType, doc: sf.Tag.Get("doc")}
+		f.def, f.hasDefault = sf.Tag.Lookup("default")
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "required":
+				f.required = true
+			case "secret":
+				f.secret = true
+			}
+		}
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+func invalid(f field, err error) error {
+	if f.secret {
+		return fmt.Errorf("%s is invalid", envKey(f.key))
+	}
+	return fmt.Errorf("%s is invalid: %w", envKey(f.key), err)
+}
+
+// isValue reports whether t is decoded from a single value, not bound field by field
+func isValue(t reflect.Type) bool {
+	return t == timeType || t == urlType || reflect.PointerTo(t).Implements(textUnmarshaler)
+}
+
+// decode converts raw, a value of the config file or a string of the environment, into v
+func decode(raw any, v reflect.Value) error {
+	switch v.Type() {
+	case durationType:
+		d, err := cast.ToDurationE(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := cast.ToTimeE(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case urlType:
+		s, err := cast.ToStringE(raw)
+		if err != nil {
+			return err
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshaler) {
+		s, err := cast.ToStringE(raw)
+		if err != nil {
+			return err
+		}
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := decode(raw, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.String:
+		s, err := cast.ToStringE(raw)
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := cast.ToBoolE(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := cast.ToInt64E(raw)
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(n) {
+			return fmt.Errorf("%d overflows %s", n, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := cast.ToUint64E(raw)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("%d overflows %s", n, v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := cast.ToFloat64E(raw)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		items, err := toSlice(raw)
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for idx, item := range items {
+			if err := decode(item, slice.Index(idx)); err != nil {
+				return fmt.Errorf("item %d: %w", idx, err)
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("%w %s, map keys must be strings", errUnsupportedType, v.Type())
+		}
+		items, err := toMap(raw)
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(items))
+		for key, item := range items {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decode(item, elem); err != nil {
+				return fmt.Errorf("item %s: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("%w %s", errUnsupportedType, v.Type())
+	}
+
+	return nil
+}
+
+// toSlice accepts a list of the config file or a whitespace separated string of the environment,
+// the syntax of GetStringSlice
+func toSlice(raw any) ([]any, error) {
+	if s, ok := raw.(string); ok {
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			return nil, nil
+		}
+		items := make([]any, len(fields))
+		for idx, field := range fields {
+			items[idx] = field
+		}
+		return items, nil
+	}
+
+	rv := reflect.ValueOf(raw)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("unable to cast %#v of type %T to a slice", raw, raw)
+	}
+	items := make([]any, rv.Len())
+	for idx := range items {
+		items[idx] = rv.Index(idx).Interface()
+	}
+
+	return items, nil
+}
+
+// toMap accepts a map of the config file or a "k1=v1,k2=v2" string of the environment
+func toMap(raw any) (map[string]any, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return cast.ToStringMapE(raw)
+	}
+
+	items := make(map[string]any)
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("item %q is not key=value", strings.TrimSpace(part))
+		}
+		items[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,123 @@
+package env
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testHTTPConfig struct {
+	Addr    string        `env:"addr,required"`
+	Timeout time.Duration `env:"timeout" default:"5s" validate:"min=1ms"`
+}
+
+type testConfig struct {
+	HTTP    testHTTPConfig `env:"test.http"`
+	DSN     string         `env:"test.mysql.dsn,required,secret"`
+	Brokers []string       `env:"test.kafka.brokers" default:"localhost:9092"`
+	Retry   int            `env:"test.retry" validate:"max=10"`
+	Ignored string
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name       string
+		env        map[string]string
+		want       testConfig
+		wantErrs   []string
+		unwantErrs []string
+	}{
+		{
+			name: "test load with defaults",
+			env: map[string]string{
+				"TEST_HTTP_ADDR": ":8080",
+				"TEST_MYSQL_DSN": "user:passwd@tcp(localhost:3306)/db",
+			},
+			want: testConfig{
+				HTTP:    testHTTPConfig{Addr: ":8080", Timeout: time.Second * 5},
+				DSN:     "user:passwd@tcp(localhost:3306)/db",
+				Brokers: []string{"localhost:9092"},
+			},
+		},
+		{
+			name: "test load overrides defaults",
+			env: map[string]string{
+				"TEST_HTTP_ADDR":     ":8080",
+				"TEST_HTTP_TIMEOUT":  "1s",
+				"TEST_MYSQL_DSN":     "dsn",
+				"TEST_KAFKA_BROKERS": "kafka-1:9092 kafka-2:9092",
+				"TEST_RETRY":         "3",
+			},
+			want: testConfig{
+				HTTP:    testHTTPConfig{Addr: ":8080", Timeout: time.Second},
+				DSN:     "dsn",
+				Brokers: []string{"kafka-1:9092", "kafka-2:9092"},
+				Retry:   3,
+			},
+		},
+		{
+			name: "test missing and invalid keys are aggregated",
+			env: map[string]string{
+				"TEST_HTTP_TIMEOUT": "soon",
+				"TEST_RETRY":        "three",
+			},
+			wantErrs: []string{
+				"TEST_HTTP_ADDR is required",
+				"TEST_MYSQL_DSN is required",
+				"TEST_HTTP_TIMEOUT is invalid",
+				"TEST_RETRY is invalid",
+			},
+		},
+		{
+			name: "test validation rules",
+			env: map[string]string{
+				"TEST_HTTP_ADDR": ":8080",
+				"TEST_MYSQL_DSN": "dsn",
+				"TEST_RETRY":     "11",
+			},
+			wantErrs: []string{"TEST_RETRY is invalid: Retry must be 10 or less"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := Load[testConfig]()
+			if len(tc.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("Load() = %+v, want %+v", got, tc.want)
+				}
+				return
+			}
+
+			if !errors.Is(err, errInvalidConfig) {
+				t.Fatalf("Load() error = %v, want %v", err, errInvalidConfig)
+			}
+			for _, want := range tc.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %v, want %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Secret(t *testing.T) {
+	type secretConfig struct {
+		Port int `env:"test.secret.port,secret"`
+	}
+	t.Setenv("TEST_SECRET_PORT", "s3cr3t")
+
+	_, err := Load[secretConfig]()
+	if err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("Load() error = %v, want an error without the secret value", err)
+	}
+}
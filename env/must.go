@@ -7,54 +7,38 @@ import (
 )
 
 func required(key string) string {
-	return fmt.Sprintf("%s is required", strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+	return fmt.Sprintf("%s is required", envKey(key))
+}
+
+// envKey returns the environment variable form of key, e.g. http.server.addr -> HTTP_SERVER_ADDR
+func envKey(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
 }
 
 func MustString(key string) string {
-	if immutable.IsSet(key) {
-		return immutable.GetString(key)
-	}
-	panic(required(key))
+	return std.MustString(key)
 }
 
 func MustStringSlice(key string) []string {
-	if immutable.IsSet(key) {
-		return immutable.GetStringSlice(key)
-	}
-	panic(required(key))
+	return std.MustStringSlice(key)
 }
 
 func MustInt(key string) int {
-	if immutable.IsSet(key) {
-		return immutable.GetInt(key)
-	}
-	panic(required(key))
+	return std.MustInt(key)
 }
 
 func MustIntSlice(key string) []int {
-	if immutable.IsSet(key) {
-		return immutable.GetIntSlice(key)
-	}
-	panic(required(key))
+	return std.MustIntSlice(key)
 }
 
 func MustUint(key string) uint {
-	if immutable.IsSet(key) {
-		return immutable.GetUint(key)
-	}
-	panic(required(key))
+	return std.MustUint(key)
 }
 
 func MustDuration(key string) time.Duration {
-	if immutable.IsSet(key) {
-		return immutable.GetDuration(key)
-	}
-	panic(required(key))
+	return std.MustDuration(key)
 }
 
 func MustFloat64(key string) float64 {
-	if immutable.IsSet(key) {
-		return immutable.GetFloat64(key)
-	}
-	panic(required(key))
+	return std.MustFloat64(key)
 }
@@ -1,6 +1,7 @@
 package env
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -9,7 +10,9 @@ import (
 
 type option func(*config)
 
-func withEnvPrefix(envPrefix string) option {
+// WithEnvPrefix reads the environment variables prefixed by envPrefix,
+// e.g. PAYMENTS_HTTP_SERVER_ADDR for http.server.addr with the prefix "payments"
+func WithEnvPrefix(envPrefix string) option {
 	return func(c *config) {
 		// if we use envPrefix => make sure it is not empty
 		if strings.TrimSpace(envPrefix) != "" {
@@ -21,22 +24,33 @@ func withEnvPrefix(envPrefix string) option {
 // WithFile inject config from config file into *config
 func WithFile(configFile string) option {
 	return func(c *config) {
+		configFile = strings.TrimSpace(configFile)
+		c.files = append(c.files, configFile)
+
 		_, err := os.Stat(configFile)
 		if err != nil {
 			slog.Warn(fmt.Sprintf("cannot apply configuration settings from %s. Please check permissions, ensure the file exists, or ignore if reading from environment variables.", configFile))
+			c.err = errors.Join(c.err, err)
 			return
 		}
 
-		configFile = strings.TrimSpace(configFile)
-		if configFile != "" {
-			c.SetConfigFile(configFile)
-		}
-
-		c.AddConfigPath(".") // set current working directory as config path
-
 		// Find and read the config file
-		if err := c.ReadInConfig(); err != nil {
+		if err := c.mergeFile(configFile); err != nil {
 			slog.Warn(fmt.Sprintf("error occur when read config from file, err: '%v', config file: '%s'", err, configFile))
+			c.err = errors.Join(c.err, err)
+		}
+	}
+}
+
+// WithValues sets keys to values, they take precedence over files and environment variables
+func WithValues(values map[string]any) option {
+	return func(c *config) {
+		if c.values == nil {
+			c.values = make(map[string]struct{}, len(values))
+		}
+		for key, value := range values {
+			c.Set(key, value)
+			c.values[normalizeKey(key)] = struct{}{}
 		}
 	}
 }
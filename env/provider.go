@@ -0,0 +1,85 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LayerProvider is the kind of the Layer of a value read from a Provider
+const LayerProvider = "provider"
+
+// the maximum time to read the values of a provider when the config is built
+const providerTimeout = time.Second * 5
+
+// Provider is a source of config values, e.g. a central key-value store
+type Provider interface {
+	// Get returns the values of the keys under prefix, keys are "." separated, e.g. http.server.addr.
+	// An empty prefix returns every key.
+	Get(ctx context.Context, prefix string) (map[string]any, error)
+	// Watch calls onChange each time a value under prefix changes, it blocks until ctx is done
+	Watch(ctx context.Context, prefix string, onChange func()) error
+}
+
+// providerLayer is a provider merged into the config and the keys it supplies
+type providerLayer struct {
+	name     string
+	provider Provider
+	prefix   string
+	keys     map[string]struct{}
+}
+
+// WithProvider reads the keys under prefix from p, named name in Source and Dump.
+// Provider values have the lowest precedence: config files and environment variables override them.
+// If p is unavailable, the last values read from it are used, the config is invalid
+// if it has never been read. Watch reloads the config when p reports a change.
+func WithProvider(name string, p Provider, prefix string) option {
+	// the last known good values, kept across reloads because Reload applies the same option
+	cache := &providerCache{}
+
+	return func(c *config) {
+		ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+		defer cancel()
+
+		values, err := p.Get(ctx, prefix)
+		if err != nil {
+			var ok bool
+			if values, ok = cache.load(); !ok {
+				c.err = errors.Join(c.err, fmt.Errorf("read provider %s: %w", name, err))
+				return
+			}
+			slog.Warn(fmt.Sprintf("provider %s is unavailable, use the last known good values", name), "err", err)
+		} else {
+			cache.store(values)
+		}
+
+		layer := providerLayer{name: name, provider: p, prefix: prefix, keys: make(map[string]struct{}, len(values))}
+		for key, value := range values {
+			// defaults are below the config files and the environment in viper
+			c.SetDefault(key, value)
+			layer.keys[normalizeKey(key)] = struct{}{}
+		}
+		c.providers = append(c.providers, layer)
+	}
+}
+
+type providerCache struct {
+	mu     sync.Mutex
+	values map[string]any
+	ok     bool
+}
+
+func (c *providerCache) load() (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values, c.ok
+}
+
+func (c *providerCache) store(values map[string]any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values, c.ok = values, true
+}
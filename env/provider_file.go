@@ -0,0 +1,109 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileTreeProvider is a Provider reading a directory where each file is a key and its content the value,
+// dir/http/server/addr and dir/http.server.addr both hold http.server.addr.
+// This is the layout of Kubernetes ConfigMaps and Secrets mounted as volumes, hidden files are ignored.
+type FileTreeProvider struct {
+	dir string
+}
+
+var _ Provider = (*FileTreeProvider)(nil)
+
+func NewFileTreeProvider(dir string) *FileTreeProvider {
+	return &FileTreeProvider{dir: filepath.Clean(dir)}
+}
+
+func (p *FileTreeProvider) Get(_ context.Context, prefix string) (map[string]any, error) {
+	values := make(map[string]any)
+	err := filepath.WalkDir(p.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != p.dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(p.dir, path)
+		if err != nil {
+			return err
+		}
+		key := strings.ReplaceAll(filepath.ToSlash(rel), "/", ".")
+		if !hasKeyPrefix(normalizeKey(key), normalizeKey(prefix)) {
+			return nil
+		}
+		// follows the symlinks of the Kubernetes volumes
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		values[key] = strings.TrimSpace(string(b))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read file tree %s: %w", p.dir, err)
+	}
+
+	return values, nil
+}
+
+func (p *FileTreeProvider) Watch(ctx context.Context, _ string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	err = filepath.WalkDir(p.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if path != p.dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+	if err != nil {
+		return fmt.Errorf("watch file tree %s: %w", p.dir, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// watch the directories created later
+			if event.Has(fsnotify.Create) && !strings.HasPrefix(filepath.Base(event.Name), ".") {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = watcher.Add(event.Name)
+				}
+			}
+			onChange()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		}
+	}
+}
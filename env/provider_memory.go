@@ -0,0 +1,97 @@
+package env
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// MemoryProvider is a Provider holding values in memory, for tests
+type MemoryProvider struct {
+	mu       sync.Mutex
+	values   map[string]any
+	err      error
+	watchers map[int]subscriber
+	watchID  int
+}
+
+var _ Provider = (*MemoryProvider)(nil)
+
+// NewMemoryProvider returns a MemoryProvider holding a copy of values
+func NewMemoryProvider(values map[string]any) *MemoryProvider {
+	return &MemoryProvider{
+		values:   maps.Clone(values),
+		watchers: make(map[int]subscriber),
+	}
+}
+
+func (p *MemoryProvider) Get(_ context.Context, prefix string) (map[string]any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	values := make(map[string]any)
+	for key, value := range p.values {
+		if hasKeyPrefix(normalizeKey(key), normalizeKey(prefix)) {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+func (p *MemoryProvider) Watch(ctx context.Context, prefix string, onChange func()) error {
+	p.mu.Lock()
+	p.watchID++
+	id := p.watchID
+	p.watchers[id] = subscriber{prefix: prefix, fn: func([]string) { onChange() }}
+	p.mu.Unlock()
+
+	<-ctx.Done()
+
+	p.mu.Lock()
+	delete(p.watchers, id)
+	p.mu.Unlock()
+
+	return nil
+}
+
+// Set sets key to value and notifies the watchers
+func (p *MemoryProvider) Set(key string, value any) {
+	p.mu.Lock()
+	p.values[key] = value
+	p.mu.Unlock()
+	p.notify(key)
+}
+
+// Delete deletes key and notifies the watchers
+func (p *MemoryProvider) Delete(key string) {
+	p.mu.Lock()
+	delete(p.values, key)
+	p.mu.Unlock()
+	p.notify(key)
+}
+
+// SetErr makes Get fail with err until it is called with nil, to simulate an unavailable store
+func (p *MemoryProvider) SetErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// notify calls the watchers of the prefixes of key
+func (p *MemoryProvider) notify(key string) {
+	p.mu.Lock()
+	var watchers []subscriber
+	for _, w := range p.watchers {
+		if hasKeyPrefix(normalizeKey(key), normalizeKey(w.prefix)) {
+			watchers = append(watchers, w)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, w := range watchers {
+		w.fn([]string{key})
+	}
+}
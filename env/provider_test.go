@@ -0,0 +1,144 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// useOptions makes the global config built from opts as if Init(opts...) was called
+func useOptions(t *testing.T, opts ...option) {
+	t.Helper()
+
+	prevOpts, prev := initOpts, current()
+	initOpts = opts
+	immutable.Store(automaticEnv(initOpts...))
+	t.Cleanup(func() {
+		initOpts = prevOpts
+		immutable.Store(prev)
+	})
+}
+
+func TestWithProvider(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "http:\n  server:\n    addr: :8080\n")
+	t.Setenv("HTTP_SERVER_TIMEOUT", "1s")
+
+	store := NewMemoryProvider(map[string]any{
+		"http.server.addr":    ":9090",
+		"http.server.timeout": "5s",
+		"http.server.cfg":     "readTimeout=10s",
+		"log.level":           "debug",
+	})
+	useOptions(t, WithFile(file), WithProvider("kv", store, "http"))
+
+	tests := []struct {
+		key   string
+		value string
+		layer Layer
+	}{
+		{key: "http.server.addr", value: ":8080", layer: Layer{Kind: LayerFile, Name: file}},
+		{key: "http.server.timeout", value: "1s", layer: Layer{Kind: LayerEnv, Name: "HTTP_SERVER_TIMEOUT"}},
+		{key: "http.server.cfg", value: "readTimeout=10s", layer: Layer{Kind: LayerProvider, Name: "kv"}},
+	}
+	for _, tt := range tests {
+		if got := GetString(tt.key); got != tt.value {
+			t.Errorf("GetString(%q) = %q, want %q", tt.key, got, tt.value)
+		}
+		if layer, ok := Source(tt.key); !ok || layer != tt.layer {
+			t.Errorf("Source(%q) = %v, %v, want %v", tt.key, layer, ok, tt.layer)
+		}
+	}
+	// out of the prefix
+	if Default().IsSet("log.level") {
+		t.Error("log.level is set, want only the keys under http")
+	}
+
+	// the last known good values are kept while the store is unavailable
+	store.Set("http.server.cfg", "readTimeout=20s")
+	store.SetErr(errors.New("connection refused"))
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v, want the last known good values", err)
+	}
+	if got := GetString("http.server.cfg"); got != "readTimeout=10s" {
+		t.Errorf("http.server.cfg = %q, want the last known good readTimeout=10s", got)
+	}
+
+	store.SetErr(nil)
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := GetString("http.server.cfg"); got != "readTimeout=20s" {
+		t.Errorf("http.server.cfg = %q, want readTimeout=20s", got)
+	}
+}
+
+func TestWithProviderUnavailable(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryProvider(nil)
+	store.SetErr(errors.New("connection refused"))
+	if err := New(WithProvider("kv", store, "")).Err(); err == nil {
+		t.Error("Err() = nil, want error for a provider never read")
+	}
+}
+
+func TestWatchProvider(t *testing.T) {
+	store := NewMemoryProvider(map[string]any{"log.level": "info"})
+	useOptions(t, WithProvider("kv", store, "log"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := Watch(ctx); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	changed := make(chan []string, 1)
+	unsubscribe := OnChange("log", func(keys []string) { changed <- keys })
+	defer unsubscribe()
+
+	// the provider watcher is started asynchronously
+	deadline := time.After(time.Second * 5)
+	for {
+		store.Set("log.level", "debug")
+		select {
+		case keys := <-changed:
+			if want := []string{"log.level"}; !reflect.DeepEqual(keys, want) {
+				t.Errorf("changed = %v, want %v", keys, want)
+			}
+			return
+		case <-time.After(watchDebounce * 3):
+		case <-deadline:
+			t.Fatal("config not reloaded after the provider changed")
+		}
+	}
+}
+
+func TestFileTreeProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "http", "server"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "..data"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "http", "server", "addr"), ":8080\n")
+	writeFile(t, filepath.Join(dir, "http.client.timeout"), "5s")
+	writeFile(t, filepath.Join(dir, "log.level"), "debug")
+	writeFile(t, filepath.Join(dir, "..data", "http.server.addr"), ":9090")
+
+	got, err := NewFileTreeProvider(dir).Get(context.Background(), "http")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := map[string]any{"http.server.addr": ":8080", "http.client.timeout": "5s"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package env
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	subscribersMu sync.RWMutex
+	subscribers   = make(map[int]subscriber)
+	subscriberID  int
+)
+
+type subscriber struct {
+	prefix string
+	fn     func(changed []string)
+}
+
+// Reload re-reads the configuration with the options passed to Init and replaces the current one.
+// If the configuration file cannot be read, the current configuration is kept.
+func Reload() error {
+	_, err := ReloadWithRollback()
+	return err
+}
+
+// ReloadWithRollback is Reload which also returns a rollback restoring the previous configuration,
+// e.g. when the new values are rejected. The subscribers are notified of the restored keys.
+// The rollback does nothing if the configuration is replaced again in the meantime.
+func ReloadWithRollback() (rollback func(), err error) {
+	next := automaticEnv(initOpts...)
+	if next.err != nil {
+		return func() {}, fmt.Errorf("reload config: %w", next.err)
+	}
+	prev := immutable.Swap(next)
+	notify(prev, next)
+
+	return func() {
+		if immutable.CompareAndSwap(next, prev) {
+			notify(next, prev)
+		}
+	}, nil
+}
+
+// OnChange calls fn with the sorted changed keys under prefix, e.g. "http.client",
+// each time a reload changes at least one of them. An empty prefix matches every key.
+// Keys are case insensitive, the changed keys are in lower case.
+// fn is called synchronously by the reload, the config is already replaced when it runs.
+func OnChange(prefix string, fn func(changed []string)) (unsubscribe func()) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	subscriberID++
+	id := subscriberID
+	subscribers[id] = subscriber{prefix: normalizeKey(prefix), fn: fn}
+
+	return func() {
+		subscribersMu.Lock()
+		defer subscribersMu.Unlock()
+		delete(subscribers, id)
+	}
+}
+
+// notify notifies the subscribers of the keys changed from prev to next
+func notify(prev, next *config) {
+	changed := changedKeys(prev, next)
+	if len(changed) == 0 {
+		return
+	}
+
+	subscribersMu.RLock()
+	subs := make([]subscriber, 0, len(subscribers))
+	for _, sub := range subscribers {
+		subs = append(subs, sub)
+	}
+	subscribersMu.RUnlock()
+
+	for _, sub := range subs {
+		matched := slices.DeleteFunc(slices.Clone(changed), func(key string) bool {
+			return !hasKeyPrefix(key, sub.prefix)
+		})
+		if len(matched) == 0 {
+			continue
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("recover", "reason", r, "prefix", sub.prefix)
+				}
+			}()
+			sub.fn(matched)
+		}()
+	}
+}
+
+func changedKeys(prev, next *config) []string {
+	keys := append(prev.AllKeys(), next.AllKeys()...)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	var changed []string
+	for _, key := range keys {
+		if !reflect.DeepEqual(prev.Get(key), next.Get(key)) {
+			changed = append(changed, key)
+		}
+	}
+
+	return changed
+}
+
+// hasKeyPrefix reports whether key is prefix or under it, "http.client" matches "http.client.timeout"
+// but not "http.clients"
+func hasKeyPrefix(key, prefix string) bool {
+	return prefix == "" || key == prefix || strings.HasPrefix(key, prefix+".")
+}
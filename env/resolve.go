@@ -0,0 +1,144 @@
+package env
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	filePrefix   = "file://"
+	base64Prefix = "base64:"
+	// the maximum depth of nested ${KEY} references
+	maxResolveDepth = 10
+)
+
+var (
+	errUnresolvedReference = errors.New("cannot resolve reference")
+	// $$ escapes a literal $
+	referencePattern = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+	// the environment variable form of a key, e.g. MYSQL_PASSWORD
+	envNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+)
+
+// GetString returns the resolved string value of key, see resolve.
+// If a reference cannot be resolved, the error is logged and an empty string is returned.
+func (c *config) GetString(key string) string {
+	s, err := c.resolveKey(key)
+	if err != nil {
+		slog.Error(err.Error())
+		return ""
+	}
+	return s
+}
+
+// GetStringSlice returns the resolved items of key, unresolved items are logged and dropped
+func (c *config) GetStringSlice(key string) []string {
+	items := c.Viper.GetStringSlice(key)
+	resolved := make([]string, 0, len(items))
+	for _, item := range items {
+		s, secret, err := c.resolve(item, 0)
+		if err != nil {
+			slog.Error(fmt.Sprintf("%s: %v", envKey(key), err))
+			continue
+		}
+		if secret {
+			MarkSecret(key)
+		}
+		resolved = append(resolved, s)
+	}
+	return resolved
+}
+
+func (c *config) resolveKey(key string) (string, error) {
+	s, secret, err := c.resolve(c.Viper.GetString(key), 0)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", envKey(key), err)
+	}
+	if secret {
+		MarkSecret(key)
+	}
+	return s, nil
+}
+
+// resolve expands the references of a raw value and reports whether the result is a secret
+//   - file:///run/secrets/db_password is replaced by the content of the file, it is a secret
+//   - base64:cGFzc3dk is replaced by the decoded value
+//   - ${OTHER_KEY} or ${other.key} is replaced by the resolved value of the key, $$ escapes $
+func (c *config) resolve(raw string, depth int) (string, bool, error) {
+	if depth > maxResolveDepth {
+		return "", false, fmt.Errorf("%w: more than %d nested references in %q", errUnresolvedReference, maxResolveDepth, raw)
+	}
+
+	switch {
+	case strings.HasPrefix(raw, filePrefix):
+		b, err := os.ReadFile(strings.TrimPrefix(raw, filePrefix))
+		if err != nil {
+			return "", false, fmt.Errorf("%w %s: %w", errUnresolvedReference, raw, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), true, nil
+	case strings.HasPrefix(raw, base64Prefix):
+		b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(raw, base64Prefix))
+		if err != nil {
+			return "", false, fmt.Errorf("%w %s...: %w", errUnresolvedReference, base64Prefix, err)
+		}
+		return string(b), false, nil
+	case !strings.Contains(raw, "$"):
+		return raw, false, nil
+	}
+
+	var (
+		secret  bool
+		errRefs error
+	)
+	s := referencePattern.ReplaceAllStringFunc(raw, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		name, ok := c.referenceKey(strings.TrimSpace(match[2 : len(match)-1]))
+		if !ok {
+			errRefs = errors.Join(errRefs, fmt.Errorf("%w %s: %s is not set", errUnresolvedReference, match, name))
+			return ""
+		}
+		value, refSecret, err := c.resolve(c.Viper.GetString(name), depth+1)
+		if err != nil {
+			errRefs = errors.Join(errRefs, err)
+			return ""
+		}
+		secret = secret || refSecret || IsSecret(name)
+		return value
+	})
+	if errRefs != nil {
+		return "", false, errRefs
+	}
+
+	return s, secret, nil
+}
+
+// referenceKey returns the key a ${name} reference points to and whether it is set.
+// An environment variable name which is not set maps to the dotted key of the files,
+// e.g. MYSQL_PASSWORD to mysql.password.
+func (c *config) referenceKey(name string) (string, bool) {
+	if name == "" {
+		return name, false
+	}
+	if c.IsSet(name) {
+		return name, true
+	}
+	if envNamePattern.MatchString(name) {
+		if key := strings.ToLower(strings.ReplaceAll(name, "_", ".")); c.IsSet(key) {
+			return key, true
+		}
+	}
+	return name, false
+}
+
+// normalizeKey returns the form of key used for lookups, keys are case insensitive
+func normalizeKey(key string) string {
+	return strings.ToLower(key)
+}
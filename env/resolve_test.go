@@ -0,0 +1,110 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatalf("write secret file error = %v", err)
+	}
+
+	t.Setenv("TEST_RESOLVE_PASSWORD", "file://"+secretFile)
+	t.Setenv("TEST_RESOLVE_DSN", "user:${TEST_RESOLVE_PASSWORD}@tcp(localhost:3306)/db")
+	t.Setenv("TEST_RESOLVE_TOKEN", "base64:dG9rZW4=")
+	t.Setenv("TEST_RESOLVE_PRICE", "$$10 for ${test.resolve.token}")
+	t.Setenv("TEST_RESOLVE_MISSING", "user:${TEST_RESOLVE_UNSET}@tcp(localhost:3306)/db")
+	t.Setenv("TEST_RESOLVE_LOOP", "${TEST_RESOLVE_LOOP}")
+	t.Setenv("TEST_RESOLVE_NO_FILE", "file:///not/exist")
+
+	testCases := []struct {
+		key        string
+		want       string
+		wantSecret bool
+		wantErr    string
+	}{
+		{key: "test.resolve.password", want: "s3cr3t", wantSecret: true},
+		{key: "test.resolve.dsn", want: "user:s3cr3t@tcp(localhost:3306)/db", wantSecret: true},
+		{key: "test.resolve.token", want: "token"},
+		{key: "test.resolve.price", want: "$10 for token"},
+		{key: "test.resolve.missing", wantErr: "${TEST_RESOLVE_UNSET}: TEST_RESOLVE_UNSET is not set"},
+		{key: "test.resolve.loop", wantErr: "nested references"},
+		{key: "test.resolve.no_file", wantErr: "file:///not/exist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			got, err := current().resolveKey(tc.key)
+			if tc.wantErr != "" {
+				if !errors.Is(err, errUnresolvedReference) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("resolveKey() error = %v, want %s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveKey() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("resolveKey() = %s, want %s", got, tc.want)
+			}
+			if IsSecret(tc.key) != tc.wantSecret {
+				t.Errorf("IsSecret() = %v, want %v", IsSecret(tc.key), tc.wantSecret)
+			}
+		})
+	}
+}
+
+func TestResolve_FileKey(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "mysql:\n  password: pw\n  dsn: u:${MYSQL_PASSWORD}@x\n  user: ${mysql.user_name}\n")
+	useConfigFile(t, file)
+
+	testCases := []struct {
+		key     string
+		want    string
+		wantErr string
+	}{
+		{key: "mysql.dsn", want: "u:pw@x"},
+		{key: "mysql.user", wantErr: "mysql.user_name is not set"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			got, err := current().resolveKey(tc.key)
+			if tc.wantErr != "" {
+				if !errors.Is(err, errUnresolvedReference) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("resolveKey() error = %v, want %s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveKey() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("resolveKey() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	secret := Secret("s3cr3t")
+
+	var buf strings.Builder
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("connect", "password", secret)
+	for _, out := range []string{fmt.Sprint(secret), fmt.Sprintf("%#v", secret), buf.String()} {
+		if strings.Contains(out, "s3cr3t") {
+			t.Errorf("secret is not redacted: %s", out)
+		}
+	}
+	if secret.Reveal() != "s3cr3t" {
+		t.Errorf("Reveal() = %s, want s3cr3t", secret.Reveal())
+	}
+}
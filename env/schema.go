@@ -0,0 +1,309 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var byteSizeType = reflect.TypeOf(ByteSize(0))
+
+// KeyDoc documents a key bound by a typed config struct, see Load for the tags.
+// The description is read from the doc tag: doc:"address the server listens on".
+type KeyDoc struct {
+	Key         string
+	EnvVar      string
+	Type        string
+	Default     string
+	HasDefault  bool
+	Required    bool
+	Secret      bool
+	Description string
+
+	typ reflect.Type
+}
+
+// Describe returns the keys bound by T in field order
+func Describe[T any]() ([]KeyDoc, error) {
+	var cfg T
+	v := reflect.ValueOf(&cfg).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w, got %s", errNotStruct, v.Type())
+	}
+
+	c := current()
+	fields := structFields(v, "", v.Type().Name())
+	docs := make([]KeyDoc, 0, len(fields))
+	for _, f := range fields {
+		docs = append(docs, KeyDoc{
+			Key:         f.key,
+			EnvVar:      c.envVarName(f.key),
+			Type:        typeName(f.value.Type()),
+			Default:     f.def,
+			HasDefault:  f.hasDefault,
+			Required:    f.required,
+			Secret:      f.secret,
+			Description: f.doc,
+			typ:         f.value.Type(),
+		})
+	}
+
+	return docs, nil
+}
+
+// SampleYAML writes a sample config file of T, each key is commented with its description
+// and environment variable, and set to its default value
+func SampleYAML[T any](w io.Writer) error {
+	docs, err := Describe[T]()
+	if err != nil {
+		return err
+	}
+
+	// group the keys by parent, in the order they first appear
+	root := &yamlNode{}
+	for _, doc := range docs {
+		node := root
+		for _, name := range strings.Split(doc.Key, ".") {
+			node = node.child(name)
+		}
+		node.doc = &doc
+	}
+
+	var b strings.Builder
+	if err := root.write(&b, -1); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+type yamlNode struct {
+	name     string
+	doc      *KeyDoc
+	children []*yamlNode
+}
+
+func (n *yamlNode) child(name string) *yamlNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &yamlNode{name: name}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *yamlNode) write(b *strings.Builder, depth int) error {
+	if n.doc == nil {
+		if depth >= 0 {
+			fmt.Fprintf(b, "%s%s:\n", indent(depth), n.name)
+		}
+		for _, c := range n.children {
+			if err := c.write(b, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	doc := n.doc
+	comment := doc.Description
+	if flags := doc.flags(); flags != "" {
+		comment = strings.TrimSpace(comment + " (" + flags + ")")
+	}
+	if comment != "" {
+		fmt.Fprintf(b, "%s# %s\n", indent(depth), comment)
+	}
+	fmt.Fprintf(b, "%s# env: %s, type: %s\n", indent(depth), doc.EnvVar, doc.Type)
+
+	value := ""
+	if doc.HasDefault && !doc.Secret {
+		def := schemaDefault(*doc)
+		// a list is kept in its whitespace separated form, it fits on the line of the key
+		if reflect.ValueOf(def).Kind() == reflect.Slice {
+			def = doc.Default
+		}
+		out, err := yaml.Marshal(def)
+		if err != nil {
+			return err
+		}
+		value = " " + strings.TrimSpace(string(out))
+	}
+	fmt.Fprintf(b, "%s%s:%s\n", indent(depth), n.name, value)
+
+	return nil
+}
+
+// JSONSchema writes the JSON Schema of the config file of T, to validate config files in CI
+func JSONSchema[T any](w io.Writer) error {
+	docs, err := Describe[T]()
+	if err != nil {
+		return err
+	}
+
+	root := schemaObject()
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	for _, doc := range docs {
+		path := strings.Split(doc.Key, ".")
+		parent := root
+		for _, name := range path[:len(path)-1] {
+			props := parent["properties"].(map[string]any)
+			child, ok := props[name].(map[string]any)
+			if !ok {
+				child = schemaObject()
+				props[name] = child
+			}
+			parent = child
+		}
+
+		prop := schemaOf(doc.typ)
+		if doc.Description != "" {
+			prop["description"] = doc.Description
+		}
+		if doc.Secret {
+			prop["writeOnly"] = true
+		}
+		if doc.HasDefault && !doc.Secret {
+			prop["default"] = schemaDefault(doc)
+		}
+		name := path[len(path)-1]
+		parent["properties"].(map[string]any)[name] = prop
+		if doc.Required && !doc.HasDefault {
+			parent["required"] = append(parent["required"].([]string), name)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// MarkdownTable writes the keys of T as a markdown table for the documentation
+func MarkdownTable[T any](w io.Writer) error {
+	docs, err := Describe[T]()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("| Key | Environment variable | Type | Default | Required | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, doc := range docs {
+		def := ""
+		if doc.HasDefault {
+			def = "`" + doc.Default + "`"
+			if doc.Secret {
+				def = redacted
+			}
+		}
+		required := ""
+		if doc.Required && !doc.HasDefault {
+			required = "yes"
+		}
+		description := doc.Description
+		if doc.Secret {
+			description = strings.TrimSpace(description + " (secret)")
+		}
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			doc.Key, doc.EnvVar, doc.Type, def, required, strings.ReplaceAll(description, "|", `\|`))
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func (d KeyDoc) flags() string {
+	var flags []string
+	if d.Required && !d.HasDefault {
+		flags = append(flags, "required")
+	}
+	if d.Secret {
+		flags = append(flags, "secret")
+	}
+	return strings.Join(flags, ", ")
+}
+
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func typeName(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "duration"
+	case byteSizeType:
+		return "byte size"
+	case timeType:
+		return "time"
+	case urlType:
+		return "url"
+	case secretType:
+		return "string"
+	}
+	if t.Kind() == reflect.Pointer {
+		return typeName(t.Elem())
+	}
+	return t.String()
+}
+
+func schemaObject() map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"properties": map[string]any{},
+		"required":   []string{},
+	}
+}
+
+// schemaOf returns the JSON Schema of a value of type t in the config file
+func schemaOf(t reflect.Type) map[string]any {
+	switch t {
+	case durationType:
+		return map[string]any{"type": "string", "pattern": `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`}
+	case byteSizeType:
+		return map[string]any{"type": []string{"string", "integer"}}
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case urlType:
+		return map[string]any{"type": "string", "format": "uri"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// schemaDefault returns the default of doc as the JSON value of the config file
+func schemaDefault(doc KeyDoc) any {
+	switch doc.typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Slice:
+		if doc.typ == durationType || doc.typ == byteSizeType {
+			return doc.Default
+		}
+		v := reflect.New(doc.typ).Elem()
+		if err := decode(doc.Default, v); err == nil {
+			return v.Interface()
+		}
+	}
+	return doc.Default
+}
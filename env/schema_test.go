@@ -0,0 +1,116 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type testSchemaConfig struct {
+	HTTP struct {
+		Addr    string        `env:"addr,required" doc:"address the server listens on"`
+		Timeout time.Duration `env:"timeout" default:"5s"`
+	} `env:"http.server"`
+	DSN     string   `env:"mysql.dsn,required,secret"`
+	Brokers []string `env:"kafka.brokers" default:"localhost:9092"`
+	Retry   int      `env:"http.client.retry" default:"3"`
+	Body    ByteSize `env:"http.server.maxBody" default:"4MiB"`
+}
+
+func TestSampleYAML(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	if err := SampleYAML[testSchemaConfig](&out); err != nil {
+		t.Fatalf("SampleYAML() error = %v", err)
+	}
+
+	want := `http:
+  server:
+    # address the server listens on (required)
+    # env: HTTP_SERVER_ADDR, type: string
+    addr:
+    # env: HTTP_SERVER_TIMEOUT, type: duration
+    timeout: 5s
+    # env: HTTP_SERVER_MAXBODY, type: byte size
+    maxBody: 4MiB
+  client:
+    # env: HTTP_CLIENT_RETRY, type: int
+    retry: 3
+mysql:
+  # (required, secret)
+  # env: MYSQL_DSN, type: string
+  dsn:
+kafka:
+  # env: KAFKA_BROKERS, type: []string
+  brokers: localhost:9092
+`
+	if out.String() != want {
+		t.Errorf("SampleYAML() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	var parsed map[string]any
+	if err := yaml.Unmarshal(out.Bytes(), &parsed); err != nil {
+		t.Errorf("sample is not valid yaml: %v", err)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	if err := JSONSchema[testSchemaConfig](&out); err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Required   []string `json:"required"`
+			Properties map[string]struct {
+				Required   []string                  `json:"required"`
+				Properties map[string]map[string]any `json:"properties"`
+			} `json:"properties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("unmarshal schema error = %v", err)
+	}
+
+	server := schema.Properties["http"].Properties["server"]
+	if got := strings.Join(server.Required, ","); got != "addr" {
+		t.Errorf("http.server required = %q, want addr", got)
+	}
+	if got := server.Properties["timeout"]["default"]; got != "5s" {
+		t.Errorf("http.server.timeout default = %v, want 5s", got)
+	}
+	if got := schema.Properties["http"].Properties["client"].Properties["retry"]; got["type"] != "integer" || got["default"] != float64(3) {
+		t.Errorf("http.client.retry = %v, want an integer defaulting to 3", got)
+	}
+	if got := schema.Properties["mysql"].Required; len(got) != 1 || got[0] != "dsn" {
+		t.Errorf("mysql required = %v, want [dsn]", got)
+	}
+}
+
+func TestMarkdownTable(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	if err := MarkdownTable[testSchemaConfig](&out); err != nil {
+		t.Fatalf("MarkdownTable() error = %v", err)
+	}
+
+	for _, line := range []string{
+		"| `http.server.addr` | `HTTP_SERVER_ADDR` | string |  | yes | address the server listens on |",
+		"| `http.server.timeout` | `HTTP_SERVER_TIMEOUT` | duration | `5s` |  |  |",
+		"| `mysql.dsn` | `MYSQL_DSN` | string |  | yes | (secret) |",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("MarkdownTable() =\n%s\nmissing %s", out.String(), line)
+		}
+	}
+}
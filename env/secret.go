@@ -0,0 +1,62 @@
+package env
+
+import (
+	"log/slog"
+	"sync"
+)
+
+const redacted = "******"
+
+// keys whose values are secrets, marked by MarkSecret, the secret option of Load
+// or because they are resolved from a file:// reference
+var secretKeys sync.Map
+
+// Secret is a string which is redacted when it is printed, logged or marshaled,
+// use Reveal to get the value
+type Secret string
+
+// GetSecret returns the value of key as a Secret and marks the key as a secret
+func GetSecret(key string) Secret {
+	return std.GetSecret(key)
+}
+
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return redacted
+}
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// MarkSecret marks the values of keys as secrets, so they are redacted when dumped
+func MarkSecret(keys ...string) {
+	for _, key := range keys {
+		secretKeys.Store(normalizeKey(key), struct{}{})
+	}
+}
+
+// IsSecret reports whether the value of key is a secret
+func IsSecret(key string) bool {
+	_, ok := secretKeys.Load(normalizeKey(key))
+	return ok
+}
+
+// Redact returns value, or a redacted placeholder if key is a secret
+func Redact(key string, value any) any {
+	if IsSecret(key) {
+		return redacted
+	}
+	return value
+}
@@ -0,0 +1,155 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// ErrNotSet is returned by Get when the key has no value
+var ErrNotSet = errors.New("key is not set")
+
+// ParseError reports a value which cannot be parsed as the requested type
+type ParseError struct {
+	Key   string
+	Value any
+	Type  string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	if IsSecret(e.Key) {
+		// the cause may quote the value
+		return fmt.Sprintf("%s: cannot parse %s as %s", envKey(e.Key), redacted, e.Type)
+	}
+	return fmt.Sprintf("%s: cannot parse %q as %s: %v", envKey(e.Key), fmt.Sprint(e.Value), e.Type, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Get returns the value of key parsed as T. Besides the types of Load, T can be
+// a map with string keys, time.Time, url.URL, ByteSize, a pointer to one of them
+// or any type implementing encoding.TextUnmarshaler.
+// The error wraps ErrNotSet if key is not set, or is a *ParseError.
+func Get[T any](key string) (T, error) {
+	return GetFrom[T](std, key)
+}
+
+// GetFrom is like Get but reads key from cfg
+func GetFrom[T any](cfg *Config, key string) (T, error) {
+	var value T
+
+	key = cfg.key(key)
+	cnf := cfg.load()
+	if !cnf.IsSet(key) {
+		return value, fmt.Errorf("%s: %w", envKey(key), ErrNotSet)
+	}
+
+	raw := cnf.Get(key)
+	if _, ok := raw.(string); ok {
+		resolved, err := cnf.resolveKey(key)
+		if err != nil {
+			return value, err
+		}
+		raw = resolved
+	}
+
+	v := reflect.ValueOf(&value).Elem()
+	if err := decode(raw, v); err != nil {
+		return value, &ParseError{Key: key, Value: raw, Type: v.Type().String(), Err: err}
+	}
+
+	return value, nil
+}
+
+// MustGet is like Get but panics if key is not set or cannot be parsed
+func MustGet[T any](key string) T {
+	value, err := Get[T](key)
+	if err != nil {
+		panic(err.Error())
+	}
+	return value
+}
+
+// GetWithDefault get the value from the environment if it exists, otherwise return the default value.
+// If the value cannot be parsed as T, the error is logged and the default value is returned.
+func GetWithDefault[T any](key string, defaultValue T) T {
+	return GetFromWithDefault(std, key, defaultValue)
+}
+
+// GetFromWithDefault is like GetWithDefault but reads key from cfg
+func GetFromWithDefault[T any](cfg *Config, key string, defaultValue T) T {
+	value, err := GetFrom[T](cfg, key)
+	if err != nil {
+		if !errors.Is(err, ErrNotSet) {
+			slog.Error(err.Error())
+		}
+		return defaultValue
+	}
+	return value
+}
+
+// getOrZero returns the value of key parsed as T, a parse error is logged and the zero value is returned
+func getOrZero[T any](cfg *Config, key string) T {
+	value, err := GetFrom[T](cfg, key)
+	if err != nil && !errors.Is(err, ErrNotSet) {
+		slog.Error(err.Error())
+	}
+	return value
+}
+
+func (c *Config) GetStringMap(key string) map[string]any {
+	return c.load().GetStringMap(c.key(key))
+}
+
+func (c *Config) GetStringMapString(key string) map[string]string {
+	return getOrZero[map[string]string](c, key)
+}
+
+// GetByteSize returns the value of key parsed as a ByteSize, e.g. "64MB"
+func (c *Config) GetByteSize(key string) ByteSize {
+	return getOrZero[ByteSize](c, key)
+}
+
+// GetURL returns the value of key parsed as a URL, nil if it is not set or invalid
+func (c *Config) GetURL(key string) *url.URL {
+	return getOrZero[*url.URL](c, key)
+}
+
+// GetTime returns the value of key parsed as a time, RFC 3339 and the formats of cast.ToTimeE are accepted
+func (c *Config) GetTime(key string) time.Time {
+	return getOrZero[time.Time](c, key)
+}
+
+func GetBool(key string) bool {
+	return std.GetBool(key)
+}
+
+func GetStringMap(key string) map[string]any {
+	return std.GetStringMap(key)
+}
+
+// GetStringMapString returns the value of key as a map, the environment form is "k1=v1,k2=v2"
+func GetStringMapString(key string) map[string]string {
+	return std.GetStringMapString(key)
+}
+
+// GetByteSize returns the value of key parsed as a ByteSize, e.g. "64MB"
+func GetByteSize(key string) ByteSize {
+	return std.GetByteSize(key)
+}
+
+// GetURL returns the value of key parsed as a URL, nil if it is not set or invalid
+func GetURL(key string) *url.URL {
+	return std.GetURL(key)
+}
+
+// GetTime returns the value of key parsed as a time, RFC 3339 and the formats of cast.ToTimeE are accepted
+func GetTime(key string) time.Time {
+	return std.GetTime(key)
+}
@@ -0,0 +1,153 @@
+package env
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	cfg := New(WithValues(map[string]any{
+		"buffer":   "64MB",
+		"endpoint": "https://example.com:8443/api?x=1",
+		"deadline": "2026-01-02T15:04:05Z",
+		"labels":   "team=core, tier=1",
+		"weights":  map[string]any{"a": 1, "b": 2},
+		"enabled":  "yes",
+	}))
+
+	t.Run("byte size", func(t *testing.T) {
+		got, err := GetFrom[ByteSize](cfg, "buffer")
+		if err != nil || got != 64*MB {
+			t.Errorf("GetFrom[ByteSize](buffer) = %v, %v, want %v", got, err, 64*MB)
+		}
+	})
+	t.Run("url", func(t *testing.T) {
+		got, err := GetFrom[*url.URL](cfg, "endpoint")
+		if err != nil || got.Host != "example.com:8443" || got.Query().Get("x") != "1" {
+			t.Errorf("GetFrom[*url.URL](endpoint) = %v, %v", got, err)
+		}
+	})
+	t.Run("time", func(t *testing.T) {
+		got, err := GetFrom[time.Time](cfg, "deadline")
+		if want := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC); err != nil || !got.Equal(want) {
+			t.Errorf("GetFrom[time.Time](deadline) = %v, %v, want %v", got, err, want)
+		}
+	})
+	t.Run("map from string", func(t *testing.T) {
+		got, err := GetFrom[map[string]string](cfg, "labels")
+		if want := map[string]string{"team": "core", "tier": "1"}; err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFrom[map[string]string](labels) = %v, %v, want %v", got, err, want)
+		}
+	})
+	t.Run("map from file", func(t *testing.T) {
+		got, err := GetFrom[map[string]int](cfg, "weights")
+		if want := map[string]int{"a": 1, "b": 2}; err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFrom[map[string]int](weights) = %v, %v, want %v", got, err, want)
+		}
+	})
+	t.Run("not set", func(t *testing.T) {
+		if _, err := GetFrom[int](cfg, "missing"); !errors.Is(err, ErrNotSet) {
+			t.Errorf("GetFrom[int](missing) error = %v, want ErrNotSet", err)
+		}
+	})
+	t.Run("parse error", func(t *testing.T) {
+		_, err := GetFrom[time.Duration](cfg, "buffer")
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("GetFrom[time.Duration](buffer) error = %v, want *ParseError", err)
+		}
+		if msg := err.Error(); !strings.HasPrefix(msg, `BUFFER: cannot parse "64MB" as time.Duration`) {
+			t.Errorf("error = %q", msg)
+		}
+	})
+}
+
+func TestGetWithDefault(t *testing.T) {
+	restore := Override(map[string]any{"test.retry": "three", "test.timeout": "3s"})
+	defer restore()
+
+	if got := GetWithDefault("test.timeout", time.Second); got != 3*time.Second {
+		t.Errorf("GetWithDefault(test.timeout) = %s, want 3s", got)
+	}
+	if got := GetWithDefault("test.retry", 5); got != 5 {
+		t.Errorf("GetWithDefault(test.retry) = %d, want the default 5 for an invalid value", got)
+	}
+	if got := GetWithDefault("test.missing", KiB); got != KiB {
+		t.Errorf("GetWithDefault(test.missing) = %s, want 1KiB", got)
+	}
+}
+
+func TestSliceSyntax(t *testing.T) {
+	type hostsConfig struct {
+		Spaces []string `env:"test.slice.spaces"`
+		Commas []string `env:"test.slice.commas"`
+	}
+
+	// the environment lists items separated by whitespace, like viper does
+	t.Setenv("TEST_SLICE_SPACES", "a b  c")
+	t.Setenv("TEST_SLICE_COMMAS", "a,b")
+	cfg := New()
+
+	testCases := []struct {
+		key  string
+		want []string
+	}{
+		{key: "test.slice.spaces", want: []string{"a", "b", "c"}},
+		{key: "test.slice.commas", want: []string{"a,b"}},
+	}
+	for _, tc := range testCases {
+		if got := cfg.GetStringSlice(tc.key); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("GetStringSlice(%s) = %q, want %q", tc.key, got, tc.want)
+		}
+		if got := cfg.MustStringSlice(tc.key); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("MustStringSlice(%s) = %q, want %q", tc.key, got, tc.want)
+		}
+		if got := GetFromWithDefault(cfg, tc.key, []string{"default"}); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("GetFromWithDefault(%s) = %q, want %q", tc.key, got, tc.want)
+		}
+	}
+
+	loaded, err := load[hostsConfig](cfg.load())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := (hostsConfig{Spaces: testCases[0].want, Commas: testCases[1].want}); !reflect.DeepEqual(loaded, want) {
+		t.Errorf("Load() = %q, want %q", loaded, want)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    ByteSize
+		wantErr bool
+	}{
+		{in: "512", want: 512},
+		{in: "10B", want: 10},
+		{in: "64MB", want: 64 * MB},
+		{in: "64mb", want: 64 * MB},
+		{in: "1.5GiB", want: GiB + 512*MiB},
+		{in: " 2 KiB ", want: 2 * KiB},
+		{in: "10XB", wantErr: true},
+		{in: "MB", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %v, %v, want %v, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if got := (64 * MiB).String(); got != "64MiB" {
+		t.Errorf("String() = %q, want 64MiB", got)
+	}
+}
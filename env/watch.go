@@ -0,0 +1,161 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var errNothingToWatch = errors.New("no config file or provider to watch, use Init with WithFile, WithLayeredFiles or WithProvider")
+
+// the delay to wait for the editor or the orchestrator to finish writing the file
+const watchDebounce = time.Millisecond * 100
+
+// Watch reloads the configuration each time a config file or a provider passed to Init changes,
+// until ctx is done. It watches the directories of the files, so atomic renames,
+// overlays created later and Kubernetes ConfigMap updates are seen.
+// A file which cannot be read is logged and the current configuration is kept.
+func Watch(ctx context.Context) error {
+	c := current()
+	if len(c.files) == 0 && len(c.providers) == 0 {
+		return errNothingToWatch
+	}
+
+	var watcher *fsnotify.Watcher
+	// the resolved target of each file, Kubernetes swaps a symlink instead of writing the file
+	realFiles := make(map[string]string, len(c.files))
+	if len(c.files) > 0 {
+		var err error
+		if watcher, err = fsnotify.NewWatcher(); err != nil {
+			return fmt.Errorf("watch config: %w", err)
+		}
+		for _, file := range c.files {
+			file = filepath.Clean(file)
+			realFiles[file], _ = filepath.EvalSymlinks(file)
+			if slices.Contains(watcher.WatchList(), filepath.Dir(file)) {
+				continue
+			}
+			if err := watcher.Add(filepath.Dir(file)); err != nil {
+				_ = watcher.Close()
+				return fmt.Errorf("watch config: %w", err)
+			}
+		}
+	}
+
+	changes := make(chan struct{}, 1)
+	for _, p := range c.providers {
+		go func() {
+			err := p.provider.Watch(ctx, p.prefix, func() {
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("watch provider failed", "provider", p.name, "err", err)
+			}
+		}()
+	}
+
+	go func() {
+		// nil channels block, so a config without files only waits for the providers
+		var events <-chan fsnotify.Event
+		var errs <-chan error
+		if watcher != nil {
+			defer watcher.Close()
+			events, errs = watcher.Events, watcher.Errors
+		}
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				for file, realFile := range realFiles {
+					current, _ := filepath.EvalSymlinks(file)
+					if filepath.Clean(event.Name) == file || current != realFile {
+						realFiles[file] = current
+						debounce = time.After(watchDebounce)
+					}
+				}
+			case <-changes:
+				debounce = time.After(watchDebounce)
+			case err, ok := <-errs:
+				if !ok {
+					return
+				}
+				slog.Warn("watch config failed", "files", c.files, "err", err)
+			case <-debounce:
+				debounce = nil
+				if err := Reload(); err != nil {
+					slog.Warn("keep the current config", "files", c.files, "err", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Snapshot holds the latest valid T bound by Load, it is replaced atomically
+// each time a reload changes one of the keys of T
+type Snapshot[T any] struct {
+	value       atomic.Pointer[T]
+	unsubscribe func()
+}
+
+// NewSnapshot binds T with Load and keeps it up to date,
+// a reload producing an invalid T is logged and the previous snapshot is kept
+func NewSnapshot[T any]() (*Snapshot[T], error) {
+	cfg, err := Load[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot[T]{}
+	s.value.Store(&cfg)
+
+	var keys []string
+	for _, f := range structFields(reflect.ValueOf(&cfg).Elem(), "", "") {
+		keys = append(keys, normalizeKey(f.key))
+	}
+	s.unsubscribe = OnChange("", func(changed []string) {
+		// a map field, e.g. labels, changes through its leaf keys, e.g. labels.a
+		if !slices.ContainsFunc(changed, func(key string) bool {
+			return slices.ContainsFunc(keys, func(field string) bool { return hasKeyPrefix(key, field) })
+		}) {
+			return
+		}
+
+		next, err := Load[T]()
+		if err != nil {
+			slog.Warn("keep the current config snapshot", "err", err)
+			return
+		}
+		s.value.Store(&next)
+	})
+
+	return s, nil
+}
+
+// Load returns the current snapshot, it must not be modified
+func (s *Snapshot[T]) Load() *T {
+	return s.value.Load()
+}
+
+// Close stops updating the snapshot
+func (s *Snapshot[T]) Close() {
+	s.unsubscribe()
+}
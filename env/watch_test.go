@@ -0,0 +1,167 @@
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useConfigFile makes the global config read file as if Init(WithFile(file)) was called
+func useConfigFile(t *testing.T, file string) {
+	t.Helper()
+	useOptions(t, WithFile(file))
+}
+
+func writeFile(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config file error = %v", err)
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "http:\n  client:\n    timeout: 5s\n  server:\n    addr: :8080\nlog:\n  level: info\n")
+	useConfigFile(t, file)
+
+	var got []string
+	unsubscribe := OnChange("http.client", func(changed []string) {
+		got = append(got, changed...)
+	})
+	defer unsubscribe()
+
+	writeFile(t, file, "http:\n  client:\n    timeout: 1s\n  server:\n    addr: :8080\nlog:\n  level: debug\n")
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if want := []string{"http.client.timeout"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changed = %v, want %v", got, want)
+	}
+	if GetDuration("http.client.timeout") != time.Second {
+		t.Errorf("http.client.timeout = %s, want 1s", GetDuration("http.client.timeout"))
+	}
+
+	// an unreadable file keeps the current config
+	writeFile(t, file, "http: [")
+	if err := Reload(); err == nil {
+		t.Error("Reload() error = nil, want error for invalid yaml")
+	}
+	if GetString("log.level") != "debug" {
+		t.Errorf("log.level = %s, want debug", GetString("log.level"))
+	}
+}
+
+func TestReloadWithRollback(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "ratelimit:\n  rps: 100\n")
+	useConfigFile(t, file)
+
+	var got [][]string
+	unsubscribe := OnChange("ratelimit", func(changed []string) {
+		got = append(got, changed)
+	})
+	defer unsubscribe()
+
+	writeFile(t, file, "ratelimit:\n  rps: -1\n")
+	rollback, err := ReloadWithRollback()
+	if err != nil {
+		t.Fatalf("ReloadWithRollback() error = %v", err)
+	}
+	if GetInt("ratelimit.rps") != -1 {
+		t.Fatalf("ratelimit.rps = %d, want -1", GetInt("ratelimit.rps"))
+	}
+
+	// the new values are rejected
+	rollback()
+	if GetInt("ratelimit.rps") != 100 {
+		t.Errorf("ratelimit.rps = %d after rollback, want 100", GetInt("ratelimit.rps"))
+	}
+	if want := [][]string{{"ratelimit.rps"}, {"ratelimit.rps"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changed = %v, want %v", got, want)
+	}
+
+	// a rollback does not override a later reload
+	writeFile(t, file, "ratelimit:\n  rps: 200\n")
+	rollback, err = ReloadWithRollback()
+	if err != nil {
+		t.Fatalf("ReloadWithRollback() error = %v", err)
+	}
+	writeFile(t, file, "ratelimit:\n  rps: 300\n")
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	rollback()
+	if GetInt("ratelimit.rps") != 300 {
+		t.Errorf("ratelimit.rps = %d after a stale rollback, want 300", GetInt("ratelimit.rps"))
+	}
+}
+
+func TestWatch(t *testing.T) {
+	type logConfig struct {
+		Level   string            `env:"log.level,required"`
+		MaxSize int               `env:"log.file.maxSize"`
+		Labels  map[string]string `env:"log.labels"`
+	}
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, file, "log:\n  level: info\n  file:\n    maxSize: 10\n  labels:\n    app: one\n")
+	useConfigFile(t, file)
+
+	snapshot, err := NewSnapshot[logConfig]()
+	if err != nil {
+		t.Fatalf("NewSnapshot() error = %v", err)
+	}
+	defer snapshot.Close()
+
+	var (
+		mu      sync.Mutex
+		changed []string
+	)
+	unsubscribe := OnChange("log.File", func(keys []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changed = keys
+	})
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := Watch(ctx); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	before := snapshot.Load()
+	// only the camelCase key changes
+	writeFile(t, file, "log:\n  level: info\n  file:\n    maxSize: 20\n  labels:\n    app: one\n")
+
+	deadline := time.Now().Add(time.Second * 2)
+	for snapshot.Load().MaxSize != 20 {
+		if time.Now().After(deadline) {
+			t.Fatalf("snapshot max size = %d, want 20", snapshot.Load().MaxSize)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if before.MaxSize != 10 {
+		t.Errorf("previous snapshot is modified, max size = %d", before.MaxSize)
+	}
+
+	mu.Lock()
+	if want := []string{"log.file.maxsize"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+	mu.Unlock()
+
+	// only a leaf key of the map field changes, e.g. log.labels.app
+	writeFile(t, file, "log:\n  level: info\n  file:\n    maxSize: 20\n  labels:\n    app: two\n")
+	deadline = time.Now().Add(time.Second * 2)
+	for snapshot.Load().Labels["app"] != "two" {
+		if time.Now().After(deadline) {
+			t.Fatalf("snapshot labels = %v, want app: two", snapshot.Load().Labels)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
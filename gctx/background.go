@@ -0,0 +1,173 @@
+package gctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// the default timeout of a background task
+const defaultTaskTimeout = time.Minute
+
+var (
+	// ErrTasksClosed is returned by Go while Wait is running
+	ErrTasksClosed = errors.New("background tasks are closed")
+	// ErrTasksAbandoned is returned by Wait when tasks are still running at its deadline
+	ErrTasksAbandoned = errors.New("background tasks abandoned")
+)
+
+// Tasks tracks background tasks detached from the request which spawns them,
+// so the shutdown waits for them
+type Tasks struct {
+	logger  Logger
+	mu      sync.Mutex
+	waiting int // the number of running Wait, Go is refused while it is not 0
+	running int
+	idle    chan struct{} // closed when running drops to 0, replaced when a task starts
+}
+
+// the Tasks used by the Go function and waited by core.Instance
+var defaultTasks = NewTasks()
+
+// DefaultTasks returns the Tasks used by the Go function
+func DefaultTasks() *Tasks {
+	return defaultTasks
+}
+
+func NewTasks(opts ...option) *Tasks {
+	t := &Tasks{
+		logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+type option func(*Tasks)
+
+// WithLogger allow use your own logger style
+// if it is not set or equal to nil, a JSON slog.Logger writing to stdout is used
+func WithLogger(l Logger) option {
+	return func(t *Tasks) {
+		if l != nil {
+			t.logger = l
+		}
+	}
+}
+
+type task struct {
+	name    string
+	timeout time.Duration
+}
+
+type TaskOption func(*task)
+
+// TaskName names the task in logs
+func TaskName(name string) TaskOption {
+	return func(t *task) {
+		t.name = name
+	}
+}
+
+// TaskTimeout overrides the default timeout of the task, 1 minute
+func TaskTimeout(timeout time.Duration) TaskOption {
+	return func(t *task) {
+		if timeout > 0 {
+			t.timeout = timeout
+		}
+	}
+}
+
+// Go runs fn in the background with the DefaultTasks, see Tasks.Go
+func Go(ctx context.Context, fn func(ctx context.Context) error, opts ...TaskOption) error {
+	return defaultTasks.Go(ctx, fn, opts...)
+}
+
+// Go runs fn in a new goroutine with a context which keeps the values of ctx, e.g. the Metadata,
+// but is not canceled with it, so the work outlives the request. The context of fn has its own timeout.
+// An error or a panic of fn is logged with the Metadata of ctx.
+// Go returns ErrTasksClosed while Wait is running, the caller may run fn itself.
+func (t *Tasks) Go(ctx context.Context, fn func(ctx context.Context) error, opts ...TaskOption) error {
+	cnf := &task{name: "background", timeout: defaultTaskTimeout}
+	for _, opt := range opts {
+		opt(cnf)
+	}
+
+	// Add under the lock, so it never races with a running Wait
+	t.mu.Lock()
+	if t.waiting > 0 {
+		t.mu.Unlock()
+		return ErrTasksClosed
+	}
+	if t.running == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.running++
+	t.mu.Unlock()
+
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		defer t.done()
+
+		ctx, cancel := context.WithTimeout(detached, cnf.timeout)
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				t.logger.Error("recover", "reason", r, "task", cnf.name, "metadata", GetMetadata(ctx))
+			}
+		}()
+
+		if err := fn(ctx); err != nil {
+			t.logger.Error("background task failed", "task", cnf.name, "metadata", GetMetadata(ctx), "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// done counts a task out
+func (t *Tasks) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running--
+	if t.running == 0 {
+		close(t.idle)
+	}
+}
+
+// Running returns the number of running tasks
+func (t *Tasks) Running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.running
+}
+
+// Wait stops accepting tasks and waits for the running ones until ctx is done.
+// Tasks are accepted again once Wait returns, so a Tasks shared in the process, e.g. the DefaultTasks,
+// outlives the shutdown of an Instance.
+func (t *Tasks) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	t.waiting++
+	running, idle := t.running, t.idle
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.waiting--
+		t.mu.Unlock()
+	}()
+
+	if running == 0 {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %d still running", ErrTasksAbandoned, t.Running())
+	}
+}
@@ -0,0 +1,169 @@
+package gctx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logRecorder records the messages logged as errors
+type logRecorder struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *logRecorder) Info(msg string, args ...any) {}
+func (r *logRecorder) Warn(msg string, args ...any) {}
+func (r *logRecorder) Error(msg string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+}
+
+func TestTasks_Go(t *testing.T) {
+	t.Parallel()
+
+	tasks := NewTasks()
+	ctx, cancel := context.WithCancel(InjectRequestID(context.Background(), "rid-1"))
+
+	result := make(chan string, 1)
+	err := tasks.Go(ctx, func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 10)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		result <- RequestID(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Go() error = %v", err)
+	}
+	// the response is written, the request context is canceled
+	cancel()
+
+	if err := tasks.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	select {
+	case rid := <-result:
+		if rid != "rid-1" {
+			t.Errorf("RequestID() = %q, want rid-1", rid)
+		}
+	default:
+		t.Error("task canceled with the request context")
+	}
+	if err := tasks.Go(ctx, func(context.Context) error { return nil }); err != nil {
+		t.Errorf("Go() after Wait error = %v, want nil", err)
+	}
+}
+
+func TestTasks_GoDuringWait(t *testing.T) {
+	t.Parallel()
+
+	tasks := NewTasks()
+	release := make(chan struct{})
+	_ = tasks.Go(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- tasks.Wait(context.Background())
+	}()
+
+	// Wait closes the Tasks asynchronously, retry until it is closed
+	var err error
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if err = tasks.Go(context.Background(), func(context.Context) error { return nil }); errors.Is(err, ErrTasksClosed) {
+			break
+		}
+	}
+	if !errors.Is(err, ErrTasksClosed) {
+		t.Errorf("Go() during Wait error = %v, want %v", err, ErrTasksClosed)
+	}
+
+	close(release)
+	if err := <-waited; err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if err := tasks.Go(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Errorf("Go() after Wait error = %v, want nil", err)
+	}
+}
+
+func TestTasks_TimeoutAndPanic(t *testing.T) {
+	t.Parallel()
+
+	logger := &logRecorder{}
+	tasks := NewTasks(WithLogger(logger))
+
+	_ = tasks.Go(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, TaskName("timeout"), TaskTimeout(time.Millisecond*10))
+	_ = tasks.Go(context.Background(), func(ctx context.Context) error {
+		panic("boom")
+	}, TaskName("panic"))
+
+	if err := tasks.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if len(logger.msgs) != 2 {
+		t.Errorf("logged = %v, want the timeout and the panic", logger.msgs)
+	}
+}
+
+func TestTasks_WaitAbandoned(t *testing.T) {
+	t.Parallel()
+
+	tasks := NewTasks()
+	release := make(chan struct{})
+	defer close(release)
+	_ = tasks.Go(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	if err := tasks.Wait(ctx); !errors.Is(err, ErrTasksAbandoned) {
+		t.Errorf("Wait() error = %v, want %v", err, ErrTasksAbandoned)
+	}
+	if tasks.Running() != 1 {
+		t.Errorf("Running() = %d, want 1", tasks.Running())
+	}
+}
+
+func TestTasks_GoAfterAbandonedWait(t *testing.T) {
+	t.Parallel()
+
+	tasks := NewTasks()
+	release := make(chan struct{})
+	_ = tasks.Go(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	if err := tasks.Wait(ctx); !errors.Is(err, ErrTasksAbandoned) {
+		t.Fatalf("Wait() error = %v, want %v", err, ErrTasksAbandoned)
+	}
+
+	// the abandoned task is still running, a new one is accepted and waited too
+	if err := tasks.Go(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Go() after an abandoned Wait error = %v", err)
+	}
+	close(release)
+	if err := tasks.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if tasks.Running() != 0 {
+		t.Errorf("Running() = %d, want 0", tasks.Running())
+	}
+}
@@ -2,6 +2,8 @@ package gctx
 
 import (
 	"context"
+	"log/slog"
+	"net/http"
 )
 
 type requestIDKeyType struct{}
@@ -23,3 +25,187 @@ func RequestID(ctx context.Context) string {
 	}
 	return rid
 }
+
+// headers of the Metadata fields
+const (
+	HeaderRequestID     = "X-Request-ID"
+	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderUserID        = "X-User-ID"
+	HeaderTenantID      = "X-Tenant-ID"
+	HeaderClientIP      = "X-Client-IP"
+	HeaderUserAgent     = "User-Agent"
+	HeaderLocale        = "Accept-Language"
+)
+
+type metadataKeyType struct{}
+
+var metadataKey metadataKeyType
+
+// Metadata is the identity of a request, read by usecases, logs and outbound calls.
+// It is a value, a copy is independent of the original.
+type Metadata struct {
+	RequestID     string `json:"request_id,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	UserID        string `json:"user_id,omitempty"`
+	TenantID      string `json:"tenant_id,omitempty"`
+	ClientIP      string `json:"client_ip,omitempty"`
+	UserAgent     string `json:"user_agent,omitempty"`
+	Locale        string `json:"locale,omitempty"`
+}
+
+// InjectMetadata replaces the Metadata of ctx, the request ID is also injected for RequestID
+func InjectMetadata(ctx context.Context, md Metadata) context.Context {
+	ctx = context.WithValue(ctx, metadataKey, md)
+	if md.RequestID != "" {
+		ctx = InjectRequestID(ctx, md.RequestID)
+	}
+	return ctx
+}
+
+// GetMetadata returns the Metadata of ctx, the request ID injected by InjectRequestID included
+func GetMetadata(ctx context.Context) Metadata {
+	md, _ := ctx.Value(metadataKey).(Metadata)
+	if rid := RequestID(ctx); rid != "" {
+		md.RequestID = rid
+	}
+	return md
+}
+
+// MergeMetadata merges md into the Metadata of ctx, see Metadata.Merge
+func MergeMetadata(ctx context.Context, md Metadata) context.Context {
+	return InjectMetadata(ctx, GetMetadata(ctx).Merge(md))
+}
+
+// Merge returns a copy of m where the fields set in other override those of m
+func (m Metadata) Merge(other Metadata) Metadata {
+	for _, f := range []struct{ dst, src *string }{
+		{&m.RequestID, &other.RequestID},
+		{&m.CorrelationID, &other.CorrelationID},
+		{&m.UserID, &other.UserID},
+		{&m.TenantID, &other.TenantID},
+		{&m.ClientIP, &other.ClientIP},
+		{&m.UserAgent, &other.UserAgent},
+		{&m.Locale, &other.Locale},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	return m
+}
+
+// Headers returns the fields set in m keyed by their header, e.g. X-Request-ID,
+// to propagate m over HTTP or in message headers
+func (m Metadata) Headers() map[string]string {
+	headers := make(map[string]string)
+	for name, value := range map[string]string{
+		HeaderRequestID:     m.RequestID,
+		HeaderCorrelationID: m.CorrelationID,
+		HeaderUserID:        m.UserID,
+		HeaderTenantID:      m.TenantID,
+		HeaderClientIP:      m.ClientIP,
+		HeaderUserAgent:     m.UserAgent,
+		HeaderLocale:        m.Locale,
+	} {
+		if value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// SetHeader sets the fields set in m on h
+func (m Metadata) SetHeader(h http.Header) {
+	for name, value := range m.Headers() {
+		h.Set(name, value)
+	}
+}
+
+// MetadataFromHeaders is the inverse of Metadata.Headers, header names are case insensitive
+func MetadataFromHeaders(headers map[string]string) Metadata {
+	h := make(http.Header, len(headers))
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+	return MetadataFromHeader(h)
+}
+
+// MetadataFromHeader reads the Metadata fields from the headers of a request
+func MetadataFromHeader(h http.Header) Metadata {
+	return Metadata{
+		RequestID:     h.Get(HeaderRequestID),
+		CorrelationID: h.Get(HeaderCorrelationID),
+		UserID:        h.Get(HeaderUserID),
+		TenantID:      h.Get(HeaderTenantID),
+		ClientIP:      h.Get(HeaderClientIP),
+		UserAgent:     h.Get(HeaderUserAgent),
+		Locale:        h.Get(HeaderLocale),
+	}
+}
+
+// LogValue logs the fields set in m as a group
+func (m Metadata) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for _, f := range []struct{ key, value string }{
+		{"request_id", m.RequestID},
+		{"correlation_id", m.CorrelationID},
+		{"user_id", m.UserID},
+		{"tenant_id", m.TenantID},
+		{"client_ip", m.ClientIP},
+		{"user_agent", m.UserAgent},
+		{"locale", m.Locale},
+	} {
+		if f.value != "" {
+			attrs = append(attrs, slog.String(f.key, f.value))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func InjectCorrelationID(ctx context.Context, id string) context.Context {
+	return MergeMetadata(ctx, Metadata{CorrelationID: id})
+}
+
+func CorrelationID(ctx context.Context) string {
+	return GetMetadata(ctx).CorrelationID
+}
+
+func InjectUserID(ctx context.Context, id string) context.Context {
+	return MergeMetadata(ctx, Metadata{UserID: id})
+}
+
+func UserID(ctx context.Context) string {
+	return GetMetadata(ctx).UserID
+}
+
+func InjectTenantID(ctx context.Context, id string) context.Context {
+	return MergeMetadata(ctx, Metadata{TenantID: id})
+}
+
+func TenantID(ctx context.Context) string {
+	return GetMetadata(ctx).TenantID
+}
+
+func InjectClientIP(ctx context.Context, ip string) context.Context {
+	return MergeMetadata(ctx, Metadata{ClientIP: ip})
+}
+
+func ClientIP(ctx context.Context) string {
+	return GetMetadata(ctx).ClientIP
+}
+
+func InjectUserAgent(ctx context.Context, ua string) context.Context {
+	return MergeMetadata(ctx, Metadata{UserAgent: ua})
+}
+
+func UserAgent(ctx context.Context) string {
+	return GetMetadata(ctx).UserAgent
+}
+
+func InjectLocale(ctx context.Context, locale string) context.Context {
+	return MergeMetadata(ctx, Metadata{Locale: locale})
+}
+
+func Locale(ctx context.Context) string {
+	return GetMetadata(ctx).Locale
+}
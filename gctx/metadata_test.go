@@ -0,0 +1,83 @@
+package gctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := InjectRequestID(context.Background(), "rid-1")
+	ctx = InjectUserID(ctx, "u-1")
+	ctx = InjectTenantID(ctx, "t-1")
+	ctx = InjectLocale(ctx, "vi-VN")
+
+	want := Metadata{RequestID: "rid-1", UserID: "u-1", TenantID: "t-1", Locale: "vi-VN"}
+	if got := GetMetadata(ctx); got != want {
+		t.Errorf("GetMetadata() = %+v, want %+v", got, want)
+	}
+	if UserID(ctx) != "u-1" || TenantID(ctx) != "t-1" || Locale(ctx) != "vi-VN" || ClientIP(ctx) != "" {
+		t.Errorf("accessors = %q %q %q %q", UserID(ctx), TenantID(ctx), Locale(ctx), ClientIP(ctx))
+	}
+
+	// the request ID of the metadata is read by RequestID
+	ctx = InjectMetadata(ctx, Metadata{RequestID: "rid-2"})
+	if RequestID(ctx) != "rid-2" || UserID(ctx) != "" {
+		t.Errorf("after InjectMetadata RequestID() = %q, UserID() = %q, want rid-2 and empty", RequestID(ctx), UserID(ctx))
+	}
+}
+
+func TestMetadata_Merge(t *testing.T) {
+	t.Parallel()
+
+	base := Metadata{RequestID: "rid", UserID: "u-1", Locale: "en"}
+	got := base.Merge(Metadata{UserID: "u-2", ClientIP: "10.0.0.1"})
+	want := Metadata{RequestID: "rid", UserID: "u-2", Locale: "en", ClientIP: "10.0.0.1"}
+	if got != want {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+	if base.UserID != "u-1" {
+		t.Errorf("Merge() modified the receiver, UserID = %q", base.UserID)
+	}
+}
+
+func TestMetadata_Serialize(t *testing.T) {
+	t.Parallel()
+
+	md := Metadata{RequestID: "rid", CorrelationID: "cid", UserAgent: "curl/8.0", TenantID: "t-1"}
+
+	headers := md.Headers()
+	if want := map[string]string{"X-Request-ID": "rid", "X-Correlation-ID": "cid", "User-Agent": "curl/8.0", "X-Tenant-ID": "t-1"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("Headers() = %v, want %v", headers, want)
+	}
+	if got := MetadataFromHeaders(map[string]string{"x-request-id": "rid", "x-correlation-id": "cid", "user-agent": "curl/8.0", "x-tenant-id": "t-1"}); got != md {
+		t.Errorf("MetadataFromHeaders() = %+v, want %+v", got, md)
+	}
+
+	h := http.Header{}
+	md.SetHeader(h)
+	if got := MetadataFromHeader(h); got != md {
+		t.Errorf("MetadataFromHeader() = %+v, want %+v", got, md)
+	}
+
+	b, err := json.Marshal(md)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"request_id":"rid","correlation_id":"cid","tenant_id":"t-1","user_agent":"curl/8.0"}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	var out bytes.Buffer
+	slog.New(slog.NewTextHandler(&out, nil)).Info("request", "md", md)
+	if !strings.Contains(out.String(), "md.request_id=rid md.correlation_id=cid md.tenant_id=t-1") {
+		t.Errorf("log = %s", out.String())
+	}
+}
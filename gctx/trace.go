@@ -0,0 +1,184 @@
+package gctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// headers of the W3C Trace Context, https://www.w3.org/TR/trace-context/
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+)
+
+const (
+	traceparentVersion = "00"
+	// the length of a version 00 traceparent: 00-<trace id>-<span id>-<flags>
+	traceparentLen = 55
+	// the maximum length of a tracestate to propagate
+	maxTracestateLen = 512
+
+	FlagSampled byte = 0x01
+)
+
+var errInvalidTraceparent = errors.New("invalid traceparent")
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies the span of the current hop in a trace
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// the span of the caller, invalid for the root span
+	ParentSpanID SpanID
+	Flags        byte
+	// vendor specific data propagated as is
+	Tracestate string
+}
+
+type spanContextKeyType struct{}
+
+var spanContextKey spanContextKeyType
+
+// NewTrace returns the root span of a new sampled trace
+func NewTrace() SpanContext {
+	return SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Flags: FlagSampled}
+}
+
+// Child returns a new span of the trace of sc, whose parent is sc
+func (sc SpanContext) Child() SpanContext {
+	return SpanContext{
+		TraceID:      sc.TraceID,
+		SpanID:       newSpanID(),
+		ParentSpanID: sc.SpanID,
+		Flags:        sc.Flags,
+		Tracestate:   sc.Tracestate,
+	}
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// Traceparent returns the traceparent header of sc
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVersion, sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceparent parses a traceparent header and the tracestate header, which may be empty.
+// The span ID of the result is the span of the caller.
+// A tracestate longer than 512 characters is dropped.
+func ParseTraceparent(traceparent, tracestate string) (SpanContext, error) {
+	var sc SpanContext
+
+	traceparent = strings.TrimSpace(traceparent)
+	// later versions may append fields, they start with the fields of version 00
+	if len(traceparent) < traceparentLen || (len(traceparent) > traceparentLen && traceparent[traceparentLen] != '-') {
+		return sc, fmt.Errorf("%w %q", errInvalidTraceparent, traceparent)
+	}
+	parts := strings.Split(traceparent[:traceparentLen], "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, fmt.Errorf("%w %q", errInvalidTraceparent, traceparent)
+	}
+	if parts[0] == "ff" || (parts[0] == traceparentVersion && len(traceparent) != traceparentLen) {
+		return sc, fmt.Errorf("%w %q: unsupported version", errInvalidTraceparent, traceparent)
+	}
+
+	var flags [1]byte
+	for _, f := range []struct {
+		dst []byte
+		src string
+	}{
+		{sc.TraceID[:], parts[1]},
+		{sc.SpanID[:], parts[2]},
+		{flags[:], parts[3]},
+		{make([]byte, 1), parts[0]},
+	} {
+		// the IDs are lowercase hex
+		if strings.ToLower(f.src) != f.src {
+			return SpanContext{}, fmt.Errorf("%w %q: uppercase hex", errInvalidTraceparent, traceparent)
+		}
+		if _, err := hex.Decode(f.dst, []byte(f.src)); err != nil {
+			return SpanContext{}, fmt.Errorf("%w %q: %w", errInvalidTraceparent, traceparent, err)
+		}
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("%w %q: zero trace id or span id", errInvalidTraceparent, traceparent)
+	}
+	sc.Flags = flags[0]
+
+	if tracestate = strings.TrimSpace(tracestate); len(tracestate) <= maxTracestateLen {
+		sc.Tracestate = tracestate
+	}
+
+	return sc, nil
+}
+
+// InjectSpanContext sets the span of the current hop
+func InjectSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey, sc)
+}
+
+// GetSpanContext returns the span of the current hop, ok is false if there is none
+func GetSpanContext(ctx context.Context) (sc SpanContext, ok bool) {
+	sc, ok = ctx.Value(spanContextKey).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// TraceIDFromContext returns the hex trace ID of ctx, empty if there is no span
+func TraceIDFromContext(ctx context.Context) string {
+	if sc, ok := GetSpanContext(ctx); ok {
+		return sc.TraceID.String()
+	}
+	return ""
+}
+
+// SpanIDFromContext returns the hex span ID of the current hop, empty if there is no span
+func SpanIDFromContext(ctx context.Context) string {
+	if sc, ok := GetSpanContext(ctx); ok {
+		return sc.SpanID.String()
+	}
+	return ""
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,77 @@
+package gctx
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		traceparent string
+		tracestate  string
+		wantErr     bool
+		wantState   string
+	}{
+		{name: "test valid", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tracestate: "congo=t61rcWkgMzE", wantState: "congo=t61rcWkgMzE"},
+		{name: "test future version with extra fields", traceparent: "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-what-the-future-will-be-like"},
+		{name: "test version 00 with extra fields", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{name: "test version ff", traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "test zero trace id", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "test zero span id", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "test uppercase", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "test not hex", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", wantErr: true},
+		{name: "test too short", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", wantErr: true},
+		{name: "test too long tracestate", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", tracestate: strings.Repeat("a", 513)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc, err := ParseTraceparent(tc.traceparent, tc.tracestate)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseTraceparent() error = %v, wantErr %t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" {
+				t.Errorf("ParseTraceparent() = %s-%s", sc.TraceID, sc.SpanID)
+			}
+			if sc.Tracestate != tc.wantState {
+				t.Errorf("Tracestate = %q, want %q", sc.Tracestate, tc.wantState)
+			}
+		})
+	}
+}
+
+func TestSpanContext(t *testing.T) {
+	t.Parallel()
+
+	root := NewTrace()
+	if !root.IsValid() || !root.IsSampled() || root.ParentSpanID.IsValid() {
+		t.Fatalf("NewTrace() = %+v, want a valid sampled root", root)
+	}
+
+	child := root.Child()
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID || child.SpanID == root.SpanID {
+		t.Errorf("Child() = %+v, want a new span of the trace of %+v", child, root)
+	}
+
+	parsed, err := ParseTraceparent(child.Traceparent(), "")
+	if err != nil || parsed.TraceID != child.TraceID || parsed.SpanID != child.SpanID || parsed.Flags != FlagSampled {
+		t.Errorf("ParseTraceparent(%q) = %+v, %v", child.Traceparent(), parsed, err)
+	}
+
+	ctx := InjectSpanContext(context.Background(), child)
+	if TraceIDFromContext(ctx) != child.TraceID.String() || SpanIDFromContext(ctx) != child.SpanID.String() {
+		t.Errorf("TraceIDFromContext() = %q, SpanIDFromContext() = %q", TraceIDFromContext(ctx), SpanIDFromContext(ctx))
+	}
+	if _, ok := GetSpanContext(context.Background()); ok {
+		t.Error("GetSpanContext() ok = true for a context without span")
+	}
+}
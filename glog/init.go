@@ -1,7 +1,6 @@
 package glog
 
 import (
-	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -14,8 +13,14 @@ func SetDefault(prefixEnv string, slogHandler slog.Handler) {
 	slog.SetDefault(slog.New(slogHandler))
 }
 
+// SlogHandlerWithWriter returns a handler writing to writer in <prefixEnv>.format read from env.Default
 func SlogHandlerWithWriter(prefixEnv string, writer io.Writer, opts *slog.HandlerOptions) slog.Handler {
-	switch env.GetString(fmt.Sprintf("%s.format", prefixEnv)) {
+	return SlogHandlerWithConfig(env.Default(), prefixEnv, writer, opts)
+}
+
+// SlogHandlerWithConfig is like SlogHandlerWithWriter but reads <prefixEnv>.format from cfg
+func SlogHandlerWithConfig(cfg *env.Config, prefixEnv string, writer io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	switch cfg.Namespace(prefixEnv).GetString("format") {
 	case "json":
 		return slog.NewJSONHandler(writer, opts)
 	case "text":
@@ -25,15 +30,16 @@ func SlogHandlerWithWriter(prefixEnv string, writer io.Writer, opts *slog.Handle
 	}
 }
 
-func loggerWriter(prefixEnv string) io.Writer {
-	switch env.GetString(fmt.Sprintf("%s.writer", prefixEnv)) {
+func loggerWriter(cfg *env.Config, prefixEnv string) io.Writer {
+	cfg = cfg.Namespace(prefixEnv)
+	switch cfg.GetString("writer") {
 	case "stdout":
 		return os.Stdout
 	case "file":
 		rotateFile := &lumberjack.Logger{
-			Filename:   env.MustString(fmt.Sprintf("%s.file.name", prefixEnv)),
-			MaxSize:    env.MustInt(fmt.Sprintf("%s.file.maxSize", prefixEnv)),
-			MaxAge:     env.MustInt(fmt.Sprintf("%s.file.maxAge", prefixEnv)),
+			Filename:   cfg.MustString("file.name"),
+			MaxSize:    cfg.MustInt("file.maxSize"),
+			MaxAge:     cfg.MustInt("file.maxAge"),
 			MaxBackups: 0,
 			LocalTime:  true,
 			Compress:   false,
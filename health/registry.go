@@ -0,0 +1,162 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// the timeout of a check when Check.Timeout is not set
+const defaultCheckTimeout = time.Second
+
+var errNoProbe = errors.New("check has no probe")
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// CheckFunc returns nil when the checked dependency is healthy
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named probe registered as a liveness or readiness check.
+// A failing critical check fails the whole report, a failing non critical check is only reported.
+type Check struct {
+	Name     string
+	Probe    CheckFunc
+	Timeout  time.Duration
+	Critical bool
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	Critical bool   `json:"critical"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the outcome of all liveness or readiness checks
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Registry holds the liveness and readiness checks of an instance, it is safe for concurrent use
+type Registry struct {
+	mu        sync.RWMutex
+	liveness  []Check
+	readiness []Check
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// RegisterLiveness adds checks telling whether the process must be restarted
+func (r *Registry) RegisterLiveness(checks ...Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.liveness = append(r.liveness, checks...)
+}
+
+// RegisterReadiness adds checks telling whether the instance can receive traffic
+func (r *Registry) RegisterReadiness(checks ...Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readiness = append(r.readiness, checks...)
+}
+
+// Live runs the liveness checks
+func (r *Registry) Live(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]Check(nil), r.liveness...)
+	r.mu.RUnlock()
+
+	return run(ctx, checks)
+}
+
+// Ready runs the readiness checks
+func (r *Registry) Ready(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]Check(nil), r.readiness...)
+	r.mu.RUnlock()
+
+	return run(ctx, checks)
+}
+
+// run executes checks concurrently, each one within its own timeout
+func run(ctx context.Context, checks []Check) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make([]CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for idx, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[idx] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Critical && result.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	result := CheckResult{
+		Name:     check.Name,
+		Status:   StatusUp,
+		Critical: check.Critical,
+	}
+
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	now := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("check panic: %v", r)
+			}
+		}()
+		if check.Probe == nil {
+			errCh <- errNoProbe
+			return
+		}
+		errCh <- check.Probe(ctx)
+	}()
+
+	// do not wait for a probe ignoring its context
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result.Duration = time.Since(now).String()
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Ready(t *testing.T) {
+	t.Parallel()
+
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	testCases := []struct {
+		name       string
+		checks     []Check
+		wantStatus Status
+		wantDown   []string
+	}{
+		{
+			name: "test all checks up",
+			checks: []Check{
+				{Name: "mysql", Probe: up, Critical: true},
+				{Name: "cache", Probe: up},
+			},
+			wantStatus: StatusUp,
+		},
+		{
+			name: "test non critical check down",
+			checks: []Check{
+				{Name: "mysql", Probe: up, Critical: true},
+				{Name: "cache", Probe: down},
+			},
+			wantStatus: StatusUp,
+			wantDown:   []string{"cache"},
+		},
+		{
+			name: "test critical check timed out",
+			checks: []Check{
+				{Name: "mysql", Probe: hang, Critical: true, Timeout: time.Millisecond * 10},
+				{Name: "cache", Probe: up},
+			},
+			wantStatus: StatusDown,
+			wantDown:   []string{"mysql"},
+		},
+		{
+			name: "test check without probe",
+			checks: []Check{
+				{Name: "kafka", Critical: true},
+			},
+			wantStatus: StatusDown,
+			wantDown:   []string{"kafka"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := NewRegistry()
+			registry.RegisterReadiness(tc.checks...)
+
+			report := registry.Ready(context.Background())
+			if report.Status != tc.wantStatus {
+				t.Errorf("Ready() status = %s, want %s", report.Status, tc.wantStatus)
+			}
+
+			var gotDown []string
+			for _, result := range report.Checks {
+				if result.Status == StatusDown {
+					gotDown = append(gotDown, result.Name)
+				}
+			}
+			if len(gotDown) != len(tc.wantDown) || (len(gotDown) > 0 && gotDown[0] != tc.wantDown[0]) {
+				t.Errorf("Ready() down checks = %v, want %v", gotDown, tc.wantDown)
+			}
+		})
+	}
+}
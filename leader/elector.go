@@ -0,0 +1,195 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ngoctd314/common/core"
+)
+
+var _ core.App = (*Elector)(nil)
+
+// Elector campaigns for the leadership of key among the replicas sharing the same Lock.
+// It implements core.App, components running singleton work can depend on it
+// and check IsLeader or use OnElected.
+type Elector struct {
+	lock          Lock
+	key           string
+	id            string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	retryInterval time.Duration
+	onElected     func(ctx context.Context)
+	onLost        func()
+	logger        Logger
+
+	leader atomic.Bool
+	// cancel the context passed to onElected
+	cancelLeadership context.CancelFunc
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func New(lock Lock, key string, opts ...option) *Elector {
+	hostname, _ := os.Hostname()
+	e := &Elector{
+		lock:          lock,
+		key:           key,
+		id:            fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8]),
+		leaseDuration: time.Second * 15,
+		renewInterval: time.Second * 5,
+		retryInterval: time.Second * 5,
+		logger:        slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// ID returns the holder identity of the elector
+func (e *Elector) ID() string {
+	return e.id
+}
+
+// IsLeader reports whether the elector holds the leadership
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Start campaigns until ctx is done or Shutdown is called
+func (e *Elector) Start(ctx context.Context) {
+	e.mu.Lock()
+	if e.started || e.stopped {
+		e.mu.Unlock()
+		return
+	}
+	e.started = true
+	e.mu.Unlock()
+	defer close(e.done)
+
+	// the last successful acquire or renew
+	var renewedAt time.Time
+	for {
+		interval := e.retryInterval
+		if e.IsLeader() {
+			interval = e.renewInterval
+			renewedAt = e.renew(ctx, renewedAt)
+		} else if e.acquire(ctx) {
+			renewedAt = time.Now()
+			interval = e.renewInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-e.stop:
+			e.release()
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Shutdown stops campaigning and releases the leadership
+func (e *Elector) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	e.stopped = true
+	close(e.stop)
+	started := e.started
+	e.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *Elector) acquire(ctx context.Context) bool {
+	acquired, err := e.lock.Acquire(ctx, e.key, e.id, e.leaseDuration)
+	if err != nil {
+		e.logger.Warn("acquire leadership failed", "key", e.key, "id", e.id, "err", err)
+		return false
+	}
+	if !acquired {
+		return false
+	}
+
+	e.logger.Info("leadership acquired", "key", e.key, "id", e.id)
+	leaderCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e.cancelLeadership = cancel
+	e.leader.Store(true)
+	if e.onElected != nil {
+		go e.onElected(leaderCtx)
+	}
+
+	return true
+}
+
+// renew extends the lease, the leadership is lost when the lock says so
+// or when renewals keep failing for longer than the lease
+func (e *Elector) renew(ctx context.Context, renewedAt time.Time) time.Time {
+	renewed, err := e.lock.Renew(ctx, e.key, e.id, e.leaseDuration)
+	switch {
+	case err == nil && renewed:
+		return time.Now()
+	case err == nil:
+		e.lose("lease taken over")
+	case time.Since(renewedAt) >= e.leaseDuration:
+		e.lose(fmt.Sprintf("renew failed for longer than the lease, err: %v", err))
+	default:
+		e.logger.Warn("renew leadership failed", "key", e.key, "id", e.id, "err", err)
+	}
+
+	return renewedAt
+}
+
+func (e *Elector) lose(reason string) {
+	if !e.leader.Swap(false) {
+		return
+	}
+
+	e.logger.Warn("leadership lost", "key", e.key, "id", e.id, "reason", reason)
+	e.cancelLeadership()
+	if e.onLost != nil {
+		e.onLost()
+	}
+}
+
+func (e *Elector) release() {
+	if !e.IsLeader() {
+		return
+	}
+
+	e.lose("released")
+	ctx, cancel := context.WithTimeout(context.Background(), e.renewInterval)
+	defer cancel()
+	if err := e.lock.Release(ctx, e.key, e.id); err != nil {
+		e.logger.Warn("release leadership failed", "key", e.key, "id", e.id, "err", err)
+	}
+}
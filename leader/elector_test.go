@@ -0,0 +1,160 @@
+package leader
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestElector(lock Lock, id string, elected, lost *atomic.Int32) *Elector {
+	return New(lock, "outbox-relay",
+		WithID(id),
+		WithLease(time.Millisecond*50, time.Millisecond*5),
+		WithRetryInterval(time.Millisecond*5),
+		OnElected(func(ctx context.Context) { elected.Add(1) }),
+		OnLost(func() { lost.Add(1) }),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestElector(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock                        = NewMemoryLock()
+		electedA, lostA             atomic.Int32
+		electedB, lostB             atomic.Int32
+		electorA                    = newTestElector(lock, "a", &electedA, &lostA)
+		electorB                    = newTestElector(lock, "b", &electedB, &lostB)
+		ctx, cancel                 = context.WithCancel(context.Background())
+		shutdownCtx, cancelShutdown = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+	defer cancelShutdown()
+
+	go electorA.Start(ctx)
+	waitFor(t, electorA.IsLeader)
+	go electorB.Start(ctx)
+
+	// b stays follower while a renews its lease
+	time.Sleep(time.Millisecond * 100)
+	if electorB.IsLeader() {
+		t.Fatal("b is leader while a holds the lease")
+	}
+
+	// a releases the leadership on shutdown, b takes over
+	if err := electorA.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if electorA.IsLeader() || electedA.Load() != 1 || lostA.Load() != 1 {
+		t.Errorf("a: leader = %v, elected = %d, lost = %d", electorA.IsLeader(), electedA.Load(), lostA.Load())
+	}
+	waitFor(t, electorB.IsLeader)
+	waitFor(t, func() bool { return electedB.Load() == 1 })
+
+	if err := electorB.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+}
+
+// failingLock is a MemoryLock which fails while failing is set, e.g. the backend is unreachable
+type failingLock struct {
+	*MemoryLock
+	failing atomic.Bool
+}
+
+func (l *failingLock) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	if l.failing.Load() {
+		return false, context.DeadlineExceeded
+	}
+	return l.MemoryLock.Acquire(ctx, key, holder, ttl)
+}
+
+func (l *failingLock) Renew(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	if l.failing.Load() {
+		return false, context.DeadlineExceeded
+	}
+	return l.MemoryLock.Renew(ctx, key, holder, ttl)
+}
+
+func TestElector_RenewError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock                        = &failingLock{MemoryLock: NewMemoryLock()}
+		elected, lost               atomic.Int32
+		elector                     = newTestElector(lock, "a", &elected, &lost)
+		ctx, cancel                 = context.WithCancel(context.Background())
+		shutdownCtx, cancelShutdown = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+	defer cancelShutdown()
+
+	go elector.Start(ctx)
+	waitFor(t, elector.IsLeader)
+
+	// renewals fail for less than the lease, the elector stays leader
+	lock.failing.Store(true)
+	time.Sleep(time.Millisecond * 20)
+	lock.failing.Store(false)
+	time.Sleep(time.Millisecond * 20)
+	if !elector.IsLeader() || lost.Load() != 0 {
+		t.Fatalf("leader = %v, lost = %d after transient renew errors, want leader", elector.IsLeader(), lost.Load())
+	}
+
+	// renewals fail for longer than the lease, the leadership is lost
+	lock.failing.Store(true)
+	waitFor(t, func() bool { return lost.Load() == 1 })
+	if elector.IsLeader() {
+		t.Error("IsLeader() = true after renewals failed for longer than the lease")
+	}
+
+	if err := elector.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+}
+
+func TestMemoryLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	lock := NewMemoryLock()
+	lock.now = func() time.Time { return now }
+
+	if ok, _ := lock.Acquire(ctx, "key", "a", time.Second); !ok {
+		t.Fatal("a cannot acquire a free lock")
+	}
+	if ok, _ := lock.Acquire(ctx, "key", "b", time.Second); ok {
+		t.Fatal("b acquires a lock held by a")
+	}
+
+	// the lease of a expires without renewal
+	now = now.Add(time.Second)
+	if ok, _ := lock.Renew(ctx, "key", "a", time.Second); ok {
+		t.Fatal("a renews an expired lease")
+	}
+	if ok, _ := lock.Acquire(ctx, "key", "b", time.Second); !ok {
+		t.Fatal("b cannot acquire an expired lock")
+	}
+	if err := lock.Release(ctx, "key", "a"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if ok, _ := lock.Renew(ctx, "key", "b", time.Second); !ok {
+		t.Fatal("a releases the lock held by b")
+	}
+}
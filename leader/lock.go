@@ -0,0 +1,16 @@
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+// Lock is a leadership lock backend, a key is held by at most one holder at a time
+type Lock interface {
+	// Acquire takes key for holder during ttl, it returns false if another holder has it
+	Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	// Renew extends the lease of holder, it returns false if holder does not have key anymore
+	Renew(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	// Release gives key up if holder has it
+	Release(ctx context.Context, key, holder string) error
+}
@@ -0,0 +1,66 @@
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Lock = (*MemoryLock)(nil)
+
+// MemoryLock is a Lock shared by electors of the same process, it is meant for tests
+type MemoryLock struct {
+	mu     sync.Mutex
+	leases map[string]lease
+	now    func() time.Time
+}
+
+type lease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+func NewMemoryLock() *MemoryLock {
+	return &MemoryLock{
+		leases: make(map[string]lease),
+		now:    time.Now,
+	}
+}
+
+func (l *MemoryLock) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if current, ok := l.leases[key]; ok && current.holder != holder && now.Before(current.expiresAt) {
+		return false, nil
+	}
+	l.leases[key] = lease{holder: holder, expiresAt: now.Add(ttl)}
+
+	return true, nil
+}
+
+func (l *MemoryLock) Renew(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	current, ok := l.leases[key]
+	if !ok || current.holder != holder || !now.Before(current.expiresAt) {
+		return false, nil
+	}
+	l.leases[key] = lease{holder: holder, expiresAt: now.Add(ttl)}
+
+	return true, nil
+}
+
+func (l *MemoryLock) Release(ctx context.Context, key, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current, ok := l.leases[key]; ok && current.holder == holder {
+		delete(l.leases, key)
+	}
+
+	return nil
+}
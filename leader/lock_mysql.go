@@ -0,0 +1,125 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ Lock = (*MySQLLock)(nil)
+
+var errLockNameTooLong = errors.New("mysql lock name is longer than 64 characters")
+
+// MySQLLock is a Lock built on MySQL GET_LOCK.
+// A named lock belongs to a session, so the lock keeps a dedicated connection of db per held key
+// and the lease lasts as long as this connection, ttl is not used.
+// If the connection breaks, MySQL releases the lock and Renew reports the leadership as lost.
+// Renew keeps the connection on other errors, e.g. a timeout, closing it would release a lock still held.
+type MySQLLock struct {
+	db    *sql.DB
+	mu    sync.Mutex
+	conns map[string]*heldLock
+}
+
+// heldLock is a lock taken by holder on the session of conn
+type heldLock struct {
+	conn   *sql.Conn
+	holder string
+}
+
+// NewMySQLLock creates a lock on a pool created by conn.SQL
+func NewMySQLLock(db *sql.DB) *MySQLLock {
+	return &MySQLLock{
+		db:    db,
+		conns: make(map[string]*heldLock),
+	}
+}
+
+func (l *MySQLLock) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	if len(key) > 64 {
+		return false, fmt.Errorf("%w: %s", errLockNameTooLong, key)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lock, ok := l.conns[key]; ok {
+		// the session is shared by the process, another holder of the process has the lock
+		if lock.holder != holder {
+			return false, nil
+		}
+		held, err := isLockHeld(ctx, lock.conn, key)
+		if err == nil && held {
+			return true, nil
+		}
+		_ = lock.conn.Close()
+		delete(l.conns, key)
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// timeout 0: do not wait for the lock
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return false, nil
+	}
+	l.conns[key] = &heldLock{conn: conn, holder: holder}
+
+	return true, nil
+}
+
+func (l *MySQLLock) Renew(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.conns[key]
+	if !ok || lock.holder != holder {
+		return false, nil
+	}
+
+	held, err := isLockHeld(ctx, lock.conn, key)
+	if (err == nil && !held) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		_ = lock.conn.Close()
+		delete(l.conns, key)
+	}
+
+	return held, err
+}
+
+func (l *MySQLLock) Release(ctx context.Context, key, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.conns[key]
+	if !ok || lock.holder != holder {
+		return nil
+	}
+	delete(l.conns, key)
+	defer lock.conn.Close()
+
+	_, err := lock.conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", key)
+
+	return err
+}
+
+// isLockHeld reports whether the session of conn holds the lock
+func isLockHeld(ctx context.Context, conn *sql.Conn, key string) (bool, error) {
+	var held sql.NullBool
+	if err := conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", key).Scan(&held); err != nil {
+		return false, err
+	}
+
+	return held.Valid && held.Bool, nil
+}
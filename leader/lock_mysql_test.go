@@ -0,0 +1,162 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeMySQL emulates the named locks of MySQL, a lock belongs to a session
+type fakeMySQL struct {
+	mu     sync.Mutex
+	nextID atomic.Int64
+	locks  map[string]int64 // key -> connection id
+}
+
+func newFakeMySQL() *sql.DB {
+	return sql.OpenDB(&fakeMySQL{locks: make(map[string]int64)})
+}
+
+func (d *fakeMySQL) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: d, id: d.nextID.Add(1)}, nil
+}
+
+func (d *fakeMySQL) Driver() driver.Driver {
+	return d
+}
+
+func (d *fakeMySQL) Open(string) (driver.Conn, error) {
+	return d.Connect(context.Background())
+}
+
+type fakeConn struct {
+	db *fakeMySQL
+	id int64
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+// Close ends the session, MySQL releases its locks
+func (c *fakeConn) Close() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	for key, id := range c.db.locks {
+		if id == c.id {
+			delete(c.db.locks, key)
+		}
+	}
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return 1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if _, err := s.Query(args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	key := args[0].(string)
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	owner, locked := db.locks[key]
+	var result int64
+	switch {
+	case strings.HasPrefix(s.query, "SELECT GET_LOCK"):
+		if !locked || owner == s.conn.id {
+			db.locks[key] = s.conn.id
+			result = 1
+		}
+	case strings.HasPrefix(s.query, "SELECT IS_USED_LOCK"):
+		if locked && owner == s.conn.id {
+			result = 1
+		}
+	case strings.HasPrefix(s.query, "SELECT RELEASE_LOCK"):
+		if locked && owner == s.conn.id {
+			delete(db.locks, key)
+			result = 1
+		}
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+
+	return &fakeRows{value: result}, nil
+}
+
+type fakeRows struct {
+	value int64
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"result"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestMySQLLock_Holder(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeMySQL()
+	defer db.Close()
+
+	var (
+		ctx  = context.Background()
+		lock = NewMySQLLock(db)
+		key  = "outbox-relay"
+		ttl  = time.Second
+	)
+
+	if ok, err := lock.Acquire(ctx, key, "a", ttl); err != nil || !ok {
+		t.Fatalf("Acquire(a) = %v, %v, want true", ok, err)
+	}
+	// the same process, another holder
+	if ok, err := lock.Acquire(ctx, key, "b", ttl); err != nil || ok {
+		t.Errorf("Acquire(b) = %v, %v, want false while a holds the lock", ok, err)
+	}
+	if ok, err := lock.Renew(ctx, key, "b", ttl); err != nil || ok {
+		t.Errorf("Renew(b) = %v, %v, want false", ok, err)
+	}
+	if err := lock.Release(ctx, key, "b"); err != nil {
+		t.Errorf("Release(b) error = %v", err)
+	}
+	if ok, err := lock.Renew(ctx, key, "a", ttl); err != nil || !ok {
+		t.Errorf("Renew(a) = %v, %v, want true after b released", ok, err)
+	}
+
+	if err := lock.Release(ctx, key, "a"); err != nil {
+		t.Fatalf("Release(a) error = %v", err)
+	}
+	if ok, err := lock.Acquire(ctx, key, "b", ttl); err != nil || !ok {
+		t.Errorf("Acquire(b) = %v, %v, want true after a released", ok, err)
+	}
+}
@@ -0,0 +1,62 @@
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+type option func(*Elector)
+
+// WithID sets the holder identity of the elector, hostname and a random suffix by default
+func WithID(id string) option {
+	return func(e *Elector) {
+		if id != "" {
+			e.id = id
+		}
+	}
+}
+
+// WithLease sets how long a leadership lasts without renewal and how often the leader renews it,
+// renewInterval must be shorter than leaseDuration
+func WithLease(leaseDuration, renewInterval time.Duration) option {
+	return func(e *Elector) {
+		if leaseDuration > 0 && renewInterval > 0 && renewInterval < leaseDuration {
+			e.leaseDuration = leaseDuration
+			e.renewInterval = renewInterval
+		}
+	}
+}
+
+// WithRetryInterval sets how often a follower tries to acquire the leadership
+func WithRetryInterval(retryInterval time.Duration) option {
+	return func(e *Elector) {
+		if retryInterval > 0 {
+			e.retryInterval = retryInterval
+		}
+	}
+}
+
+// OnElected is called in its own goroutine when the elector becomes leader,
+// ctx is canceled as soon as the leadership is lost or released
+func OnElected(fn func(ctx context.Context)) option {
+	return func(e *Elector) {
+		e.onElected = fn
+	}
+}
+
+// OnLost is called when the elector stops being leader, including on Shutdown
+func OnLost(fn func()) option {
+	return func(e *Elector) {
+		e.onLost = fn
+	}
+}
+
+// WithLogger allow use your own logger style
+// if it is not set or equal to nil, a JSON slog.Logger writing to stdout is used
+func WithLogger(l Logger) option {
+	return func(e *Elector) {
+		if l != nil {
+			e.logger = l
+		}
+	}
+}
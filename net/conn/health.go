@@ -0,0 +1,18 @@
+package conn
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ngoctd314/common/health"
+)
+
+// SQLHealthCheck pings the pool, register it as a readiness check of the instance
+func SQLHealthCheck(name string, db *sql.DB, timeout time.Duration) health.Check {
+	return health.Check{
+		Name:     name,
+		Probe:    db.PingContext,
+		Timeout:  timeout,
+		Critical: true,
+	}
+}
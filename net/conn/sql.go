@@ -18,10 +18,17 @@ const (
 	MySQLDriver sqlDriver = "mysql"
 )
 
-// SQL creates a new sql.DB instance
+// SQL creates a new sql.DB instance from <driver>.<dbName>.dsn and <driver>.<dbName>.cfg,
+// read from env.Default unless WithSQLConfig is used
 // it already ping the database to make sure the connection can be established
-func SQL(driver sqlDriver, dbName string) (*sql.DB, error) {
-	db, err := sql.Open(string(driver), env.GetString(fmt.Sprintf("%s.%s.dsn", driver, dbName)))
+func SQL(driver sqlDriver, dbName string, opts ...sqlOption) (*sql.DB, error) {
+	o := &sqlOptions{cfg: env.Default()}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := o.cfg.Namespace(fmt.Sprintf("%s.%s", driver, dbName))
+	db, err := sql.Open(string(driver), cfg.GetString("dsn"))
 	if err != nil {
 		return nil, fmt.Errorf("%w, detail: %w", errInvalidDSN, err)
 	}
@@ -30,7 +37,7 @@ func SQL(driver sqlDriver, dbName string) (*sql.DB, error) {
 	// 	return nil, err
 	// }
 
-	if err := setSQLConnectionPoll(db, driver, dbName); err != nil {
+	if err := setSQLConnectionPoll(db, cfg); err != nil {
 		return nil, err
 	}
 
@@ -45,8 +52,9 @@ var (
 	errInvalidDSN             = errors.New("invalid DSN, want format user:passwd@tcp(ip:port)/dbName")
 )
 
-func setSQLConnectionPoll(db *sql.DB, driver sqlDriver, dbName string) error {
-	query, _ := url.ParseQuery(env.GetString(fmt.Sprintf("%s.%s.cfg", driver, dbName)))
+// setSQLConnectionPoll reads the cfg of the database from cfg, the namespace of the database
+func setSQLConnectionPoll(db *sql.DB, cfg *env.Config) error {
+	query, _ := url.ParseQuery(cfg.GetString("cfg"))
 
 	var errGroup error
 	maxOpenConns, err := strconv.Atoi(query.Get("maxOpenConns"))
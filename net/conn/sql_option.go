@@ -0,0 +1,18 @@
+package conn
+
+import "github.com/ngoctd314/common/env"
+
+type sqlOptions struct {
+	cfg *env.Config
+}
+
+type sqlOption func(o *sqlOptions)
+
+// WithSQLConfig reads the dsn and the cfg of the database from cfg instead of env.Default
+func WithSQLConfig(cfg *env.Config) sqlOption {
+	return func(o *sqlOptions) {
+		if cfg != nil {
+			o.cfg = cfg
+		}
+	}
+}
@@ -2,25 +2,23 @@ package conn
 
 import (
 	"errors"
-	"os"
 	"testing"
+
+	"github.com/ngoctd314/common/env/envtest"
 )
 
 func TestSQL(t *testing.T) {
+	t.Parallel()
+
 	type args struct {
 		driver sqlDriver
 		dbName string
 	}
 
-	type hook struct {
-		before func()
-		after  func()
-	}
-
 	testCases := []struct {
 		name    string
 		args    args
-		hook    hook
+		values  map[string]any
 		wantErr error
 	}{
 		{
@@ -29,15 +27,9 @@ func TestSQL(t *testing.T) {
 				driver: MySQLDriver,
 				dbName: "test",
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("MYSQL_TEST_DSN", "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True")
-					os.Setenv("MYSQL_TEST_CFG", "maxOpenConns=10&maxIdleConns=10&connMaxLifetime=10m&connMaxIdleTime=5m")
-				},
-				after: func() {
-					os.Setenv("MYSQL_TEST_DSN", "")
-					os.Setenv("MYSQL_TEST_CFG", "")
-				},
+			values: map[string]any{
+				"mysql.test.dsn": "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True",
+				"mysql.test.cfg": "maxOpenConns=10&maxIdleConns=10&connMaxLifetime=10m&connMaxIdleTime=5m",
 			},
 		},
 		{
@@ -46,13 +38,8 @@ func TestSQL(t *testing.T) {
 				driver: MySQLDriver,
 				dbName: "test",
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("MYSQL_TEST_DSN", "localhost:3306/dbName?charset=utf8mb4&loc=Local&parseTime=True")
-				},
-				after: func() {
-					os.Setenv("MYSQL_TEST_DSN", "")
-				},
+			values: map[string]any{
+				"mysql.test.dsn": "localhost:3306/dbName?charset=utf8mb4&loc=Local&parseTime=True",
 			},
 			wantErr: errInvalidDSN,
 		},
@@ -62,15 +49,9 @@ func TestSQL(t *testing.T) {
 				driver: MySQLDriver,
 				dbName: "test",
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("MYSQL_TEST_DSN", "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True")
-					os.Setenv("MYSQL_TEST_CFG", "maxIdleConns=10&connMaxLifetime=10m&connMaxIdleTime=5m")
-				},
-				after: func() {
-					os.Setenv("MYSQL_TEST_DSN", "")
-					os.Setenv("MYSQL_TEST_CFG", "")
-				},
+			values: map[string]any{
+				"mysql.test.dsn": "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True",
+				"mysql.test.cfg": "maxIdleConns=10&connMaxLifetime=10m&connMaxIdleTime=5m",
 			},
 			wantErr: errInvalidMaxOpenConns,
 		},
@@ -80,15 +61,9 @@ func TestSQL(t *testing.T) {
 				driver: MySQLDriver,
 				dbName: "test",
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("MYSQL_TEST_DSN", "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True")
-					os.Setenv("MYSQL_TEST_CFG", "maxOpenConns=10&connMaxLifetime=10m&connMaxIdleTime=5m")
-				},
-				after: func() {
-					os.Setenv("MYSQL_TEST_DSN", "")
-					os.Setenv("MYSQL_TEST_CFG", "")
-				},
+			values: map[string]any{
+				"mysql.test.dsn": "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True",
+				"mysql.test.cfg": "maxOpenConns=10&connMaxLifetime=10m&connMaxIdleTime=5m",
 			},
 			wantErr: errInvalidMaxIdleConns,
 		},
@@ -98,15 +73,9 @@ func TestSQL(t *testing.T) {
 				driver: MySQLDriver,
 				dbName: "test",
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("MYSQL_TEST_DSN", "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True")
-					os.Setenv("MYSQL_TEST_CFG", "maxOpenConns=10&maxIdleConns=10&connMaxIdleTime=5m")
-				},
-				after: func() {
-					os.Setenv("MYSQL_TEST_DSN", "")
-					os.Setenv("MYSQL_TEST_CFG", "")
-				},
+			values: map[string]any{
+				"mysql.test.dsn": "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True",
+				"mysql.test.cfg": "maxOpenConns=10&maxIdleConns=10&connMaxIdleTime=5m",
 			},
 			wantErr: errInvalidConnMaxLifetime,
 		},
@@ -116,15 +85,9 @@ func TestSQL(t *testing.T) {
 				driver: MySQLDriver,
 				dbName: "test",
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("MYSQL_TEST_DSN", "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True")
-					os.Setenv("MYSQL_TEST_CFG", "maxOpenConns=10&maxIdleConns=10&connMaxLifetime=10m")
-				},
-				after: func() {
-					os.Setenv("MYSQL_TEST_DSN", "")
-					os.Setenv("MYSQL_TEST_CFG", "")
-				},
+			values: map[string]any{
+				"mysql.test.dsn": "user:passwd@tcp(localhost:3306)/dbName?charset=utf8mb4&loc=Local&parseTime=True",
+				"mysql.test.cfg": "maxOpenConns=10&maxIdleConns=10&connMaxLifetime=10m",
 			},
 			wantErr: errInvalidConnMaxIdleTime,
 		},
@@ -132,14 +95,9 @@ func TestSQL(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.hook.before != nil {
-				tc.hook.before()
-			}
-			if tc.hook.after != nil {
-				defer tc.hook.after()
-			}
+			t.Parallel()
 
-			_, err := SQL(tc.args.driver, tc.args.dbName)
+			_, err := SQL(tc.args.driver, tc.args.dbName, WithSQLConfig(envtest.New(tc.values)))
 			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
 				t.Fatalf("SQL() error = %v, wantErr %v", err, tc.wantErr)
 			}
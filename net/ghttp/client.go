@@ -16,21 +16,28 @@ type Client interface {
 }
 
 type httpClient struct {
-	client *http.Client
+	client  *http.Client
+	cfg     *env.Config
+	tracing bool
 }
 
+// NewClient creates a client with the timeout http.client.timeout, 10s by default,
+// read from env.Default unless WithClientConfig is used
 func NewClient(opts ...clientOption) *httpClient {
-	instance := &http.Client{
-		Timeout: env.GetWithDefault("http.client.timeout", time.Second*10),
-	}
+	instance := &http.Client{}
 
 	client := &httpClient{
 		client: instance,
+		cfg:    env.Default(),
 	}
 
 	for _, opt := range opts {
 		opt(client)
 	}
+	instance.Timeout = env.GetFromWithDefault(client.cfg.Namespace("http.client"), "timeout", time.Second*10)
+	if client.tracing {
+		instance.Transport = TraceTransport(instance.Transport)
+	}
 
 	return client
 }
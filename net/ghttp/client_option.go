@@ -1,9 +1,31 @@
 package ghttp
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/ngoctd314/common/env"
+)
 
 type clientOption func(s *httpClient)
 
+// WithClientConfig reads http.client.timeout from cfg instead of env.Default,
+// e.g. WithClientConfig(env.Namespace("payments")) reads payments.http.client.timeout
+func WithClientConfig(cfg *env.Config) clientOption {
+	return func(s *httpClient) {
+		if cfg != nil {
+			s.cfg = cfg
+		}
+	}
+}
+
+// WithTracing propagates the trace of the request context with TraceTransport,
+// it wraps the transport set by WithTransport whatever the order of the options
+func WithTracing() clientOption {
+	return func(s *httpClient) {
+		s.tracing = true
+	}
+}
+
 // Transport specifies the mechanism by which individual
 // HTTP requests are made.
 // If nil, DefaultTransport is used.
@@ -7,12 +7,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngoctd314/common/apperror"
+	"github.com/ngoctd314/common/gctx"
 )
 
 func JSONSuccess(c *gin.Context, respDTO *ResponseBody) {
 	if respDTO.StatusCode == 0 {
 		respDTO.StatusCode = http.StatusOK
 	}
+	if respDTO.RequestID == "" {
+		respDTO.RequestID = gctx.RequestID(c.Request.Context())
+	}
 
 	c.JSON(respDTO.StatusCode, respDTO)
 }
@@ -23,12 +27,14 @@ func JSONFail(c *gin.Context, err error) {
 	}
 
 	httpError := apperror.ErrInternalServer(err)
+	httpError.SetRequestID(gctx.RequestID(c.Request.Context()))
 	logErr(c, &httpError.BaseError, err)
 
 	c.JSON(httpError.HTTPCode, ResponseBody{
-		Success: false,
-		Error:   httpError,
-		Message: httpError.Error(),
+		Success:   false,
+		Error:     httpError,
+		Message:   httpError.Error(),
+		RequestID: httpError.RequestID,
 	})
 }
 
@@ -39,6 +45,9 @@ func JSONAbort(c *gin.Context, err error) {
 
 func logErr(c *gin.Context, baseErr *apperror.BaseError, err error) {
 	kvs := []any{"err_id", baseErr.ID, "path", c.FullPath()}
+	if rid := gctx.RequestID(c.Request.Context()); rid != "" {
+		kvs = append(kvs, "request_id", rid)
+	}
 	if baseErr.Ancestor() != nil {
 		kvs = append(kvs, "ancestor", baseErr.Ancestor())
 	}
@@ -46,14 +55,17 @@ func logErr(c *gin.Context, baseErr *apperror.BaseError, err error) {
 }
 
 func canResolveErr(c *gin.Context, err error) bool {
+	rid := gctx.RequestID(c.Request.Context())
+
 	var baseErr *apperror.BaseError
 	if errors.As(err, &baseErr) {
 		httpErr := apperror.NewHTTPError(baseErr, http.StatusBadRequest)
-		httpErr.SetErrType("bad_request")
+		httpErr.SetErrType("bad_request").SetRequestID(rid)
 		c.JSON(httpErr.HTTPCode, ResponseBody{
-			Success: false,
-			Error:   httpErr,
-			Message: err.Error(),
+			Success:   false,
+			Error:     httpErr,
+			Message:   err.Error(),
+			RequestID: rid,
 		})
 		logErr(c, baseErr, err)
 		return true
@@ -61,13 +73,19 @@ func canResolveErr(c *gin.Context, err error) bool {
 
 	var httpErr *apperror.HTTPError
 	if errors.As(err, &httpErr) {
-		if httpErr.HTTPCode == 0 {
-			httpErr.HTTPCode = http.StatusBadRequest
+		// copy, the error may be shared between requests
+		stamped := *httpErr
+		if stamped.HTTPCode == 0 {
+			stamped.HTTPCode = http.StatusBadRequest
 		}
-		c.JSON(httpErr.HTTPCode, ResponseBody{
-			Success: false,
-			Error:   httpErr,
-			Message: httpErr.Error(),
+		if stamped.RequestID == "" {
+			stamped.RequestID = rid
+		}
+		c.JSON(stamped.HTTPCode, ResponseBody{
+			Success:   false,
+			Error:     &stamped,
+			Message:   stamped.Error(),
+			RequestID: stamped.RequestID,
 		})
 		logErr(c, &httpErr.BaseError, err)
 		return true
@@ -0,0 +1,44 @@
+package ghttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngoctd314/common/health"
+)
+
+// LivezHandler serves the liveness report of the registry,
+// it responds 200 when the report is up and 503 otherwise
+func LivezHandler(registry *health.Registry) http.Handler {
+	return healthHandler(registry.Live)
+}
+
+// ReadyzHandler serves the readiness report of the registry,
+// it responds 200 when the report is up and 503 otherwise
+func ReadyzHandler(registry *health.Registry) http.Handler {
+	return healthHandler(registry.Ready)
+}
+
+// GinHealthRoutes registers GET /livez and GET /readyz
+func GinHealthRoutes(r gin.IRoutes, registry *health.Registry) {
+	r.GET("/livez", gin.WrapH(LivezHandler(registry)))
+	r.GET("/readyz", gin.WrapH(ReadyzHandler(registry)))
+}
+
+func healthHandler(check func(ctx context.Context) health.Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := check(r.Context())
+
+		statusCode := http.StatusOK
+		if report.Status != health.StatusUp {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", MIMEApplicationJSON)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,59 @@
+package ghttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ngoctd314/common/health"
+)
+
+func TestReadyzHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		probe          health.CheckFunc
+		wantStatusCode int
+		wantStatus     health.Status
+	}{
+		{
+			name:           "test ready",
+			probe:          func(ctx context.Context) error { return nil },
+			wantStatusCode: http.StatusOK,
+			wantStatus:     health.StatusUp,
+		},
+		{
+			name:           "test not ready",
+			probe:          func(ctx context.Context) error { return errors.New("draining") },
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantStatus:     health.StatusDown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := health.NewRegistry()
+			registry.RegisterReadiness(health.Check{Name: "instance", Probe: tc.probe, Critical: true})
+
+			rec := httptest.NewRecorder()
+			ReadyzHandler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tc.wantStatusCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tc.wantStatusCode)
+			}
+			var report health.Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("decode report error = %v", err)
+			}
+			if report.Status != tc.wantStatus || len(report.Checks) != 1 {
+				t.Errorf("report = %+v, want status %s with 1 check", report, tc.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ngoctd314/common/gctx"
+)
+
+// the maximum length of a request ID accepted from the client
+const maxRequestIDLen = 128
+
+// RequestIDHandler reads the X-Request-ID header of the request, or generates a UUID if it is missing
+// or invalid, injects it with gctx.InjectRequestID and echoes it in the X-Request-ID response header
+func RequestIDHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, rid := withRequestID(r)
+		w.Header().Set(gctx.HeaderRequestID, rid)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GinRequestID is RequestIDHandler for gin, register it before the handlers
+// so JSONSuccess and JSONFail stamp the request ID
+func GinRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, rid := withRequestID(c.Request)
+		c.Header(gctx.HeaderRequestID, rid)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func withRequestID(r *http.Request) (context.Context, string) {
+	rid := r.Header.Get(gctx.HeaderRequestID)
+	if !validRequestID(rid) {
+		rid = uuid.NewString()
+	}
+	return gctx.InjectRequestID(r.Context(), rid), rid
+}
+
+// validRequestID accepts printable ASCII only, the ID is echoed in headers and logs
+func validRequestID(rid string) bool {
+	if rid == "" || len(rid) > maxRequestIDLen {
+		return false
+	}
+	for idx := range len(rid) {
+		if rid[idx] < 0x21 || rid[idx] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package ghttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngoctd314/common/apperror"
+	"github.com/ngoctd314/common/gctx"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		header    string
+		wantReuse bool
+	}{
+		{name: "test reuse the request ID", header: "rid-1", wantReuse: true},
+		{name: "test generate a missing request ID"},
+		{name: "test generate for an invalid request ID", header: "rid\x01"},
+		{name: "test generate for a too long request ID", header: strings.Repeat("a", maxRequestIDLen+1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+			handler := RequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = gctx.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(gctx.HeaderRequestID, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got == "" || rec.Header().Get(gctx.HeaderRequestID) != got {
+				t.Fatalf("request ID = %q, response header = %q", got, rec.Header().Get(gctx.HeaderRequestID))
+			}
+			if reused := got == tc.header; reused != tc.wantReuse {
+				t.Errorf("request ID = %q, header = %q, want reuse %t", got, tc.header, tc.wantReuse)
+			}
+		})
+	}
+}
+
+func TestGinRequestID(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GinRequestID())
+	r.GET("/ok", func(c *gin.Context) {
+		JSONSuccess(c, ResponseBodyOK("pong"))
+	})
+	r.GET("/bad", func(c *gin.Context) {
+		JSONFail(c, apperror.New("bad input"))
+	})
+	r.GET("/http", func(c *gin.Context) {
+		JSONFail(c, apperror.ErrBindRequest(errors.New("unexpected EOF")))
+	})
+	r.GET("/internal", func(c *gin.Context) {
+		JSONFail(c, http.ErrBodyNotAllowed)
+	})
+
+	for _, path := range []string{"/ok", "/bad", "/http", "/internal"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(gctx.HeaderRequestID, "rid-1")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(gctx.HeaderRequestID); got != "rid-1" {
+				t.Errorf("response header = %q, want rid-1", got)
+			}
+			var body struct {
+				RequestID string `json:"request_id"`
+				Error     *struct {
+					RequestID string `json:"request_id"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("unmarshal body %s error = %v", rec.Body.String(), err)
+			}
+			if body.RequestID != "rid-1" {
+				t.Errorf("body request_id = %q, want rid-1", body.RequestID)
+			}
+			if path != "/ok" && (body.Error == nil || body.Error.RequestID != "rid-1") {
+				t.Errorf("error body = %s, want request_id rid-1", rec.Body.String())
+			}
+		})
+	}
+}
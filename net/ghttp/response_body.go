@@ -12,6 +12,8 @@ type ResponseBody struct {
 	Message    string `json:"message,omitempty"`
 	Error      any    `json:"error,omitempty"`
 	Paging     any    `json:"paging,omitempty"`
+	// set by JSONSuccess from the request ID of the context, see GinRequestID
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func ResponseBodyOK(data any, opts ...func(*ResponseBody)) *ResponseBody {
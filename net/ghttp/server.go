@@ -17,6 +17,7 @@ import (
 type Server struct {
 	instance *http.Server
 	logger   Logger
+	cfg      *env.Config
 }
 
 var (
@@ -24,6 +25,10 @@ var (
 	errInvalidServerCfg = errors.New("invalid server config")
 )
 
+// NewServer creates a server from http.server.addr and http.server.cfg,
+// read from env.Default unless WithServerConfig is used, e.g. WithServerConfig(env.Namespace("payments"))
+// reads payments.http.server.addr.
+// The options take precedence over the config.
 func NewServer(handler http.Handler, opts ...serverOption) (*Server, error) {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
@@ -34,20 +39,22 @@ func NewServer(handler http.Handler, opts ...serverOption) (*Server, error) {
 	httpServer := &http.Server{
 		Handler: handler,
 	}
-	err := setServerCfg(httpServer, env.GetString("http.server.cfg"))
-	if err != nil {
-		return nil, fmt.Errorf("%w, detail: %w", errInvalidServerCfg, err)
-	}
 
 	server := &Server{
 		instance: httpServer,
 		logger:   logger,
+		cfg:      env.Default(),
 	}
 
 	for _, opt := range opts {
 		opt(server)
 	}
 
+	err := setServerCfg(httpServer, server.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w, detail: %w", errInvalidServerCfg, err)
+	}
+
 	return server, nil
 }
 
@@ -60,44 +67,58 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.instance.Shutdown(ctx)
 }
 
-func setServerCfg(server *http.Server, params string) error {
-	q, err := url.ParseQuery(params)
+// setServerCfg sets the fields of server which are not set by the options
+func setServerCfg(server *http.Server, cfg *env.Config) error {
+	cfg = cfg.Namespace("http.server")
+	q, err := url.ParseQuery(cfg.GetString("cfg"))
 	if err != nil {
 		return err
 	}
-	server.Addr = env.GetString("http.server.addr")
+	if server.Addr == "" {
+		server.Addr = cfg.GetString("addr")
+	}
 
 	var errGroup error
 
 	readHeaderTimeout, err := time.ParseDuration(q.Get("readHeaderTimeout"))
-	server.ReadHeaderTimeout = readHeaderTimeout
 	if err != nil {
 		errGroup = errors.Join(errGroup, fmt.Errorf("invalid readHeaderTimeout, error: %w", err))
 	}
+	if server.ReadHeaderTimeout == 0 {
+		server.ReadHeaderTimeout = readHeaderTimeout
+	}
 
 	readTimeout, err := time.ParseDuration(q.Get("readTimeout"))
-	server.ReadTimeout = readTimeout
 	if err != nil {
 		errGroup = errors.Join(errGroup, fmt.Errorf("invalid readTimeout, error: %w", err))
 	}
+	if server.ReadTimeout == 0 {
+		server.ReadTimeout = readTimeout
+	}
 
 	writeTimeout, err := time.ParseDuration(q.Get("writeTimeout"))
-	server.WriteTimeout = writeTimeout
 	if err != nil {
 		errGroup = errors.Join(errGroup, fmt.Errorf("invalid writeTimeout, error: %w", err))
 	}
+	if server.WriteTimeout == 0 {
+		server.WriteTimeout = writeTimeout
+	}
 
 	idleTimeout, err := time.ParseDuration(q.Get("idleTimeout"))
-	server.IdleTimeout = idleTimeout
 	if err != nil {
 		errGroup = errors.Join(errGroup, fmt.Errorf("invalid idleTimeout, error: %w", err))
 	}
+	if server.IdleTimeout == 0 {
+		server.IdleTimeout = idleTimeout
+	}
 
 	maxHeaderBytes, err := strconv.Atoi(q.Get("maxHeaderBytes"))
 	if err != nil {
 		errGroup = errors.Join(errGroup, fmt.Errorf("invalid maxHeaderBytes, error: %w", err))
 	}
-	server.MaxHeaderBytes = maxHeaderBytes
+	if server.MaxHeaderBytes == 0 {
+		server.MaxHeaderBytes = maxHeaderBytes
+	}
 
 	if errGroup != nil {
 		return errGroup
@@ -5,6 +5,8 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+
+	"github.com/ngoctd314/common/env"
 )
 
 type serverOption func(s *Server)
@@ -19,6 +21,15 @@ func WithServerLogger(l Logger) serverOption {
 	}
 }
 
+// WithServerConfig reads http.server.addr and http.server.cfg from cfg instead of env.Default
+func WithServerConfig(cfg *env.Config) serverOption {
+	return func(s *Server) {
+		if cfg != nil {
+			s.cfg = cfg
+		}
+	}
+}
+
 // DisableGeneralOptionsHandler, if true, passes "OPTIONS *" requests to the Handler,
 // otherwise responds with 200 OK and Content-Length: 0.
 func WithDisableGeneralOptionsHandler(diable bool) serverOption {
@@ -3,8 +3,10 @@ package ghttp
 import (
 	"errors"
 	"net/http"
-	"os"
 	"testing"
+	"time"
+
+	"github.com/ngoctd314/common/env/envtest"
 )
 
 func TestNewServer(t *testing.T) {
@@ -14,20 +16,10 @@ func TestNewServer(t *testing.T) {
 		handler http.Handler
 	}
 
-	type hook struct {
-		before func()
-		after  func()
-	}
-
-	after := func() {
-		os.Setenv("HTTP_SERVER_ADDR", "")
-		os.Setenv("HTTP_SERVER_CFG", "")
-	}
-
 	testCases := []struct {
 		name    string
 		args    args
-		hook    hook
+		values  map[string]any
 		wantErr error
 	}{
 		{
@@ -35,12 +27,9 @@ func TestNewServer(t *testing.T) {
 			args: args{
 				handler: http.DefaultServeMux,
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("HTTP_SERVER_ADDR", ":8080")
-					os.Setenv("HTTP_SERVER_CFG", "readHeaderTimeout=10s&readTimeout=10s&writeTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000")
-				},
-				after: after,
+			values: map[string]any{
+				"http.server.addr": ":8080",
+				"http.server.cfg":  "readHeaderTimeout=10s&readTimeout=10s&writeTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000",
 			},
 		},
 		{
@@ -55,12 +44,9 @@ func TestNewServer(t *testing.T) {
 			args: args{
 				handler: http.DefaultServeMux,
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("HTTP_SERVER_ADDR", ":8080")
-					os.Setenv("HTTP_SERVER_CFG", "readTimeout=10s&writeTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000")
-				},
-				after: after,
+			values: map[string]any{
+				"http.server.addr": ":8080",
+				"http.server.cfg":  "readTimeout=10s&writeTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000",
 			},
 			wantErr: errInvalidServerCfg,
 		},
@@ -69,12 +55,9 @@ func TestNewServer(t *testing.T) {
 			args: args{
 				handler: http.DefaultServeMux,
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("HTTP_SERVER_ADDR", ":8080")
-					os.Setenv("HTTP_SERVER_CFG", "readHeaderTimeout=10s&writeTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000")
-				},
-				after: after,
+			values: map[string]any{
+				"http.server.addr": ":8080",
+				"http.server.cfg":  "readHeaderTimeout=10s&writeTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000",
 			},
 			wantErr: errInvalidServerCfg,
 		},
@@ -83,12 +66,9 @@ func TestNewServer(t *testing.T) {
 			args: args{
 				handler: http.DefaultServeMux,
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("HTTP_SERVER_ADDR", ":8080")
-					os.Setenv("HTTP_SERVER_CFG", "readHeaderTimeout=10s&readTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000")
-				},
-				after: after,
+			values: map[string]any{
+				"http.server.addr": ":8080",
+				"http.server.cfg":  "readHeaderTimeout=10s&readTimeout=10s&idleTimeout=10s&maxHeaderBytes=1000",
 			},
 			wantErr: errInvalidServerCfg,
 		},
@@ -97,12 +77,9 @@ func TestNewServer(t *testing.T) {
 			args: args{
 				handler: http.DefaultServeMux,
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("HTTP_SERVER_ADDR", ":8080")
-					os.Setenv("HTTP_SERVER_CFG", "readHeaderTimeout=10s&readTimeout=10s&writeTimeout=10s&maxHeaderBytes=1000")
-				},
-				after: after,
+			values: map[string]any{
+				"http.server.addr": ":8080",
+				"http.server.cfg":  "readHeaderTimeout=10s&readTimeout=10s&writeTimeout=10s&maxHeaderBytes=1000",
 			},
 			wantErr: errInvalidServerCfg,
 		},
@@ -111,12 +88,9 @@ func TestNewServer(t *testing.T) {
 			args: args{
 				handler: http.DefaultServeMux,
 			},
-			hook: hook{
-				before: func() {
-					os.Setenv("HTTP_SERVER_ADDR", ":8080")
-					os.Setenv("HTTP_SERVER_CFG", "readHeaderTimeout=10s&readTimeout=10s&writeTimeout=10s&idleTimeout=10s")
-				},
-				after: after,
+			values: map[string]any{
+				"http.server.addr": ":8080",
+				"http.server.cfg":  "readHeaderTimeout=10s&readTimeout=10s&writeTimeout=10s&idleTimeout=10s",
 			},
 			wantErr: errInvalidServerCfg,
 		},
@@ -124,17 +98,32 @@ func TestNewServer(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.hook.before != nil {
-				tc.hook.before()
-			}
-			if tc.hook.after != nil {
-				defer tc.hook.after()
-			}
+			t.Parallel()
 
-			_, err := NewServer(tc.args.handler)
+			_, err := NewServer(tc.args.handler, WithServerConfig(envtest.New(tc.values)))
 			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
 				t.Fatalf("NewServer() error = %v, wantErr %v", err, tc.wantErr)
 			}
 		})
 	}
 }
+
+func TestNewServer_Namespace(t *testing.T) {
+	t.Parallel()
+
+	cfg := envtest.New(map[string]any{
+		"payments.http.server.addr": ":9090",
+		"payments.http.server.cfg":  "readHeaderTimeout=1s&readTimeout=2s&writeTimeout=3s&idleTimeout=4s&maxHeaderBytes=1000",
+	})
+	server, err := NewServer(http.DefaultServeMux, WithServerConfig(cfg.Namespace("payments")), WithMaxHeaderBytes(2000))
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if server.instance.Addr != ":9090" || server.instance.WriteTimeout != 3*time.Second {
+		t.Errorf("NewServer() addr = %s, writeTimeout = %s, want :9090 and 3s", server.instance.Addr, server.instance.WriteTimeout)
+	}
+	// options take precedence over the config
+	if server.instance.MaxHeaderBytes != 2000 {
+		t.Errorf("NewServer() maxHeaderBytes = %d, want 2000", server.instance.MaxHeaderBytes)
+	}
+}
@@ -0,0 +1,67 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngoctd314/common/gctx"
+)
+
+// TraceHandler continues the trace of the traceparent and tracestate headers of the request,
+// or starts a new one if they are missing or invalid, and injects the span of this hop
+// with gctx.InjectSpanContext
+func TraceHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(withSpan(r)))
+	})
+}
+
+// GinTrace is TraceHandler for gin
+func GinTrace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(withSpan(c.Request))
+		c.Next()
+	}
+}
+
+func withSpan(r *http.Request) context.Context {
+	parent, err := gctx.ParseTraceparent(r.Header.Get(gctx.HeaderTraceparent), r.Header.Get(gctx.HeaderTracestate))
+	if err != nil {
+		return gctx.InjectSpanContext(r.Context(), gctx.NewTrace())
+	}
+	return gctx.InjectSpanContext(r.Context(), parent.Child())
+}
+
+// traceTransport sets the traceparent and tracestate headers of outbound requests
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+// TraceTransport returns a RoundTripper which propagates the trace of the request context,
+// each request is a new child span of the span of the context, or the root of a new trace if there is none.
+// If base is nil, http.DefaultTransport is used.
+func TraceTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &traceTransport{base: base}
+}
+
+func (t *traceTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	span := gctx.NewTrace()
+	if parent, ok := gctx.GetSpanContext(r.Context()); ok {
+		span = parent.Child()
+	}
+
+	// a RoundTripper must not modify the request
+	r = r.Clone(r.Context())
+	r.Header.Set(gctx.HeaderTraceparent, span.Traceparent())
+	if span.Tracestate != "" {
+		r.Header.Set(gctx.HeaderTracestate, span.Tracestate)
+	} else {
+		r.Header.Del(gctx.HeaderTracestate)
+	}
+
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,66 @@
+package ghttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ngoctd314/common/gctx"
+)
+
+func TestTracePropagation(t *testing.T) {
+	t.Parallel()
+
+	// the downstream service records the traceparent it receives
+	var received string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(gctx.HeaderTraceparent)
+	}))
+	defer downstream.Close()
+
+	client := NewClient(WithTracing())
+	var serverSpan gctx.SpanContext
+	handler := TraceHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverSpan, _ = gctx.GetSpanContext(r.Context())
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("Do() error = %v", err)
+			return
+		}
+		resp.Body.Close()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(gctx.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if serverSpan.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || serverSpan.ParentSpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("server span = %+v, want a child of the incoming traceparent", serverSpan)
+	}
+	outbound, err := gctx.ParseTraceparent(received, "")
+	if err != nil {
+		t.Fatalf("outbound traceparent %q error = %v", received, err)
+	}
+	if outbound.TraceID != serverSpan.TraceID || outbound.SpanID == serverSpan.SpanID {
+		t.Errorf("outbound span = %s, want a new span of the trace of %s", received, serverSpan.Traceparent())
+	}
+}
+
+func TestTraceHandler_NewTrace(t *testing.T) {
+	t.Parallel()
+
+	var span gctx.SpanContext
+	var ok bool
+	handler := TraceHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span, ok = gctx.GetSpanContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(gctx.HeaderTraceparent, "invalid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !ok || span.ParentSpanID.IsValid() {
+		t.Errorf("span = %+v, %t, want the root of a new trace", span, ok)
+	}
+}
@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/ngoctd314/common/gctx"
+)
+
+// Handler processes a message received from a Subscriber
+type Handler func(ctx context.Context, msg *Message) error
+
+// InjectHeaders sets the gctx metadata of ctx on the headers of msg, see gctx.Metadata.Headers,
+// and the traceparent of a new child span if ctx has one
+func InjectHeaders(ctx context.Context, msg *Message) {
+	for key, value := range gctx.GetMetadata(ctx).Headers() {
+		msg.SetHeader(key, value)
+	}
+
+	if sc, ok := gctx.GetSpanContext(ctx); ok {
+		child := sc.Child()
+		msg.SetHeader(gctx.HeaderTraceparent, child.Traceparent())
+		if child.Tracestate != "" {
+			msg.SetHeader(gctx.HeaderTracestate, child.Tracestate)
+		}
+	}
+}
+
+// ExtractContext restores the gctx metadata and the trace of the headers of msg into ctx,
+// the consumer runs in a new child span of the span of the producer
+func ExtractContext(ctx context.Context, msg *Message) context.Context {
+	ctx = gctx.MergeMetadata(ctx, gctx.MetadataFromHeaders(msg.Headers()))
+
+	parent, err := gctx.ParseTraceparent(msg.Header(gctx.HeaderTraceparent), msg.Header(gctx.HeaderTracestate))
+	if err != nil {
+		return ctx
+	}
+	return gctx.InjectSpanContext(ctx, parent.Child())
+}
+
+// ContextHandler calls next with the metadata of the message restored into ctx
+func ContextHandler(next Handler) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		return next(ExtractContext(ctx, msg), msg)
+	}
+}
+
+type contextPublisher struct {
+	publisher Publisher
+}
+
+// ContextPublisher returns a Publisher which sets the metadata of ctx on the headers
+// of each message before publishing it with p
+func ContextPublisher(p Publisher) Publisher {
+	return &contextPublisher{publisher: p}
+}
+
+func (p *contextPublisher) Publish(ctx context.Context, msg *Message) error {
+	InjectHeaders(ctx, msg)
+	return p.publisher.Publish(ctx, msg)
+}
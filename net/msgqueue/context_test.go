@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ngoctd314/common/gctx"
+)
+
+// chanQueue publishes messages to a channel and subscribes from it
+type chanQueue chan *Message
+
+func (q chanQueue) Publish(_ context.Context, msg *Message) error {
+	q <- msg
+	return nil
+}
+
+func (q chanQueue) Subscribe(ctx context.Context) (*Message, error) {
+	select {
+	case msg := <-q:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestContextPropagation(t *testing.T) {
+	t.Parallel()
+
+	md := gctx.Metadata{RequestID: "rid-1", UserID: "u-1", TenantID: "t-1"}
+	span := gctx.NewTrace()
+	ctx := gctx.InjectSpanContext(gctx.InjectMetadata(context.Background(), md), span)
+
+	q := make(chanQueue, 1)
+	kafkaMsg := &KafkaMessage{Value: []byte("hello"), Headers: []KafkaHeader{{Key: "content-type", Value: []byte("text/plain")}}}
+	msg := NewKafkaMessage(kafkaMsg)
+	if err := ContextPublisher(q).Publish(ctx, msg); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// the headers are set on the KafkaMessage serialized by the writer, once
+	var traceparents int
+	for _, h := range kafkaMsg.Headers {
+		if h.Key == gctx.HeaderTraceparent {
+			traceparents++
+		}
+	}
+	if traceparents != 1 {
+		t.Errorf("KafkaMessage.Headers = %+v, want one %s", kafkaMsg.Headers, gctx.HeaderTraceparent)
+	}
+
+	received, err := q.Subscribe(context.Background())
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if received.Header("content-type") != "text/plain" {
+		t.Errorf("content-type = %q, want the original header kept", received.Header("content-type"))
+	}
+
+	handler := ContextHandler(func(ctx context.Context, msg *Message) error {
+		if got := gctx.GetMetadata(ctx); got != md {
+			t.Errorf("GetMetadata() = %+v, want %+v", got, md)
+		}
+		if gctx.RequestID(ctx) != "rid-1" {
+			t.Errorf("RequestID() = %q, want rid-1", gctx.RequestID(ctx))
+		}
+		consumer, ok := gctx.GetSpanContext(ctx)
+		if !ok || consumer.TraceID != span.TraceID || consumer.SpanID == span.SpanID {
+			t.Errorf("consumer span = %+v, want a span of the trace %s", consumer, span.TraceID)
+		}
+		return nil
+	})
+	if err := handler(context.Background(), received); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+}
+
+func TestExtractContext_NoHeaders(t *testing.T) {
+	t.Parallel()
+
+	ctx := ExtractContext(context.Background(), NewKafkaMessage(&KafkaMessage{}))
+	if md := gctx.GetMetadata(ctx); md != (gctx.Metadata{}) {
+		t.Errorf("GetMetadata() = %+v, want empty", md)
+	}
+	if _, ok := gctx.GetSpanContext(ctx); ok {
+		t.Error("GetSpanContext() ok = true, want no span")
+	}
+}
@@ -7,6 +7,7 @@ import (
 type KafkaWriter struct {
 }
 
+// Publish sends msg as is, wrap the writer with ContextPublisher to propagate the metadata of ctx
 func (k *KafkaWriter) Publish(ctx context.Context, msg *Message) (err error) {
 	return nil
 }
@@ -5,7 +5,13 @@ type Message struct {
 }
 
 type KafkaMessage struct {
-	Key   []byte
+	Key     []byte
+	Value   []byte
+	Headers []KafkaHeader
+}
+
+type KafkaHeader struct {
+	Key   string
 	Value []byte
 }
 
@@ -14,3 +20,44 @@ func NewKafkaMessage(msg *KafkaMessage) *Message {
 		kafakaMessage: msg,
 	}
 }
+
+// Header returns the value of the header key, empty if it is not set
+func (m *Message) Header(key string) string {
+	if m.kafakaMessage == nil {
+		return ""
+	}
+	for _, h := range m.kafakaMessage.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// SetHeader sets the header key on the underlying KafkaMessage, it replaces the previous value
+func (m *Message) SetHeader(key, value string) {
+	if m.kafakaMessage == nil {
+		m.kafakaMessage = &KafkaMessage{}
+	}
+	for idx, h := range m.kafakaMessage.Headers {
+		if h.Key == key {
+			m.kafakaMessage.Headers[idx].Value = []byte(value)
+			return
+		}
+	}
+	m.kafakaMessage.Headers = append(m.kafakaMessage.Headers, KafkaHeader{Key: key, Value: []byte(value)})
+}
+
+// Headers returns a copy of the headers of the message
+func (m *Message) Headers() map[string]string {
+	if m.kafakaMessage == nil {
+		return map[string]string{}
+	}
+	headers := make(map[string]string, len(m.kafakaMessage.Headers))
+	for _, h := range m.kafakaMessage.Headers {
+		if _, ok := headers[h.Key]; !ok {
+			headers[h.Key] = string(h.Value)
+		}
+	}
+	return headers
+}
@@ -0,0 +1,13 @@
+package scheduler
+
+type option func(*Scheduler)
+
+// WithLogger allow use your own logger style
+// if it is not set or equal to nil, a JSON slog.Logger writing to stdout is used
+func WithLogger(l Logger) option {
+	return func(s *Scheduler) {
+		if l != nil {
+			s.logger = l
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidCron = errors.New("invalid cron expression")
+
+// Schedule returns the next activation time strictly after t
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type interval time.Duration
+
+// Every activates a job at a fixed interval, the first run is one interval after start.
+// d must be positive, Scheduler.Add rejects the job otherwise.
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+// isEmptyInterval reports whether s is an interval which never moves forward
+func isEmptyInterval(s Schedule) bool {
+	i, ok := s.(interval)
+	return ok && i <= 0
+}
+
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// cronSchedule holds the allowed values of each field as a bit set
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// when both day fields are restricted, a day matches if either of them matches
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron parses a standard 5 fields expression "minute hour day-of-month month day-of-week",
+// fields accept *, lists, ranges and steps (e.g. "*/15 9-18 * * 1-5").
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are supported too.
+// Times are evaluated in the location of the time passed to Next.
+func Cron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%w %q: want %d fields, got %d", errInvalidCron, expr, len(cronFields), len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for idx, field := range fields {
+		b, err := parseCronField(field, cronFields[idx])
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", errInvalidCron, expr, err)
+		}
+		bits[idx] = b
+	}
+
+	schedule := &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	// 7 is an alias of Sunday
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	return schedule, nil
+}
+
+// MustCron is like Cron but panics if the expression cannot be parsed
+func MustCron(expr string) Schedule {
+	schedule, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if before, after, found := strings.Cut(part, "/"); found {
+			n, err := strconv.Atoi(after)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q of %s", after, spec.name)
+			}
+			rangePart, step = before, n
+		}
+
+		low, high := spec.min, spec.max
+		if rangePart != "*" {
+			before, after, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = strconv.Atoi(before); err != nil {
+				return 0, fmt.Errorf("invalid value %q of %s", before, spec.name)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(after); err != nil {
+					return 0, fmt.Errorf("invalid value %q of %s", after, spec.name)
+				}
+			} else if step > 1 {
+				// "5/15" means from 5 to max every 15
+				high = spec.max
+			}
+		}
+		if low < spec.min || high > spec.max || low > high {
+			return 0, fmt.Errorf("%s %q is out of range [%d, %d]", spec.name, part, spec.min, spec.max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// a matching time exists within 5 years unless the expression is impossible, e.g. "0 0 31 2 *"
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	from := time.Date(2024, time.January, 10, 10, 7, 30, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		expr    string
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "test every minute",
+			expr: "* * * * *",
+			want: time.Date(2024, time.January, 10, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name: "test step of minutes",
+			expr: "*/15 * * * *",
+			want: time.Date(2024, time.January, 10, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name: "test range of hours on weekdays",
+			expr: "30 9-18/3 * * 1-5",
+			want: time.Date(2024, time.January, 10, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "test list of days of week, 7 is sunday",
+			expr: "0 0 * * 6,7",
+			want: time.Date(2024, time.January, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "test day of month or day of week",
+			expr: "0 0 1 * 5",
+			want: time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "test descriptor",
+			expr: "@monthly",
+			want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "test leap day",
+			expr: "0 0 29 2 *",
+			want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "test impossible date",
+			expr: "0 0 31 2 *",
+			want: time.Time{},
+		},
+		{
+			name:    "test invalid number of fields",
+			expr:    "* * * *",
+			wantErr: errInvalidCron,
+		},
+		{
+			name:    "test value out of range",
+			expr:    "60 * * * *",
+			wantErr: errInvalidCron,
+		},
+		{
+			name:    "test invalid step",
+			expr:    "*/0 * * * *",
+			wantErr: errInvalidCron,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := Cron(tc.expr)
+			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Cron() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if got := schedule.Next(from); !got.Equal(tc.want) {
+				t.Errorf("Next() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
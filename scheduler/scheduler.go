@@ -0,0 +1,264 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ngoctd314/common/core"
+)
+
+var _ core.App = (*Scheduler)(nil)
+
+var (
+	errInvalidJob     = errors.New("invalid job")
+	errAlreadyStarted = errors.New("scheduler already started")
+)
+
+type OverlapPolicy int
+
+const (
+	// OverlapSkip drops an activation while the previous run is still in progress, this is the default policy
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue runs an activation after the previous run finishes, at most one activation is queued
+	OverlapQueue
+	// OverlapAllow runs activations concurrently
+	OverlapAllow
+)
+
+// JobFunc does the work of a job, ctx is canceled when the run times out or the scheduler shuts down
+type JobFunc func(ctx context.Context) error
+
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      JobFunc
+	Overlap  OverlapPolicy
+	// a random delay in [0, Jitter) added to every activation to spread load across replicas
+	Jitter time.Duration
+	// the maximum duration of a run, zero means no timeout
+	Timeout time.Duration
+}
+
+type job struct {
+	Job
+	running atomic.Int32
+	queue   chan struct{}
+}
+
+// Scheduler runs jobs on their schedule, it implements core.App
+//
+//	s := scheduler.New()
+//	s.Add(scheduler.Job{Name: "cleanup", Schedule: scheduler.MustCron("0 3 * * *"), Run: cleanup})
+//	core.NewInstance(ctx, nil, core.WithComponent("scheduler", s))
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []*job
+	logger  Logger
+	started bool
+	// canceled on Shutdown, every run context derives from it
+	ctx    context.Context
+	cancel context.CancelFunc
+	// track loops and runs
+	wg sync.WaitGroup
+}
+
+func New(opts ...option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add registers a job, jobs must be added before Start
+func (s *Scheduler) Add(j Job) error {
+	switch {
+	case j.Name == "":
+		return fmt.Errorf("%w: name is empty", errInvalidJob)
+	case j.Schedule == nil:
+		return fmt.Errorf("%w: %s has no schedule", errInvalidJob, j.Name)
+	case isEmptyInterval(j.Schedule):
+		return fmt.Errorf("%w: %s has an interval which is not positive", errInvalidJob, j.Name)
+	case j.Run == nil:
+		return fmt.Errorf("%w: %s has no run func", errInvalidJob, j.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return errAlreadyStarted
+	}
+	s.jobs = append(s.jobs, &job{Job: j, queue: make(chan struct{}, 1)})
+
+	return nil
+}
+
+// Start runs the jobs until ctx is done or Shutdown is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		s.logger.Error(errAlreadyStarted.Error())
+		return
+	}
+	s.started = true
+	jobs := s.jobs
+	// Add must happen before Wait of Shutdown
+	s.wg.Add(len(jobs))
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for _, j := range jobs {
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+	<-ctx.Done()
+}
+
+// Shutdown stops activating jobs, cancels the running ones and waits for them until ctx is done
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.started = true // a scheduler cannot be started after Shutdown
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs are still running: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	if j.Overlap == OverlapQueue {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-j.queue:
+					j.running.Add(1)
+					s.run(j)
+					j.running.Add(-1)
+				}
+			}
+		}()
+	}
+
+	next := j.Schedule.Next(time.Now())
+	for {
+		if next.IsZero() {
+			s.logger.Error("job has no next activation, stop scheduling it", "job", j.Name)
+			return
+		}
+
+		delay := time.Until(next)
+		if j.Jitter > 0 {
+			delay += rand.N(j.Jitter)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.trigger(j)
+		next = nextActivation(j.Schedule, next, time.Now(), j.Jitter)
+	}
+}
+
+// nextActivation returns the activation after the one planned at prev, triggered at now.
+// The jitter only delays a trigger, the activations stay on the schedule,
+// those missed because the trigger is late by more than the jitter are skipped.
+func nextActivation(schedule Schedule, prev, now time.Time, jitter time.Duration) time.Time {
+	if now.After(prev.Add(jitter)) {
+		prev = now
+	}
+	return schedule.Next(prev)
+}
+
+func (s *Scheduler) trigger(j *job) {
+	switch j.Overlap {
+	case OverlapAllow:
+		j.running.Add(1)
+	case OverlapQueue:
+		select {
+		case j.queue <- struct{}{}:
+		default:
+			s.logger.Warn("job is already queued, skip activation", "job", j.Name)
+		}
+		return
+	default:
+		// claim the slot before spawning the run, so no other activation starts meanwhile
+		if !j.running.CompareAndSwap(0, 1) {
+			s.logger.Warn("job is still running, skip activation", "job", j.Name)
+			return
+		}
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer j.running.Add(-1)
+		s.run(j)
+	}()
+}
+
+// run runs j once, the caller counts it in j.running
+func (s *Scheduler) run(j *job) {
+
+	ctx := s.ctx
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+
+	now := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("recover", "job", j.Name, "reason", r)
+		}
+	}()
+	if err := j.Run(ctx); err != nil {
+		s.logger.Error("job failed", "job", j.Name, "err", err, "elapsed", time.Since(now).String())
+		return
+	}
+	s.logger.Info("job done", "job", j.Name, "elapsed", time.Since(now).String())
+}
@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes of the logger
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		overlap   OverlapPolicy
+		run       func(ctx context.Context) error
+		wantRuns  func(runs int32) bool
+		wantLog   string
+		wantNoErr bool
+	}{
+		{
+			name:    "test skip overlapping runs",
+			overlap: OverlapSkip,
+			run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			wantRuns: func(runs int32) bool { return runs == 1 },
+			wantLog:  "job is still running, skip activation",
+		},
+		{
+			name:    "test allow overlapping runs",
+			overlap: OverlapAllow,
+			run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			wantRuns: func(runs int32) bool { return runs > 1 },
+		},
+		{
+			name:    "test queue overlapping runs",
+			overlap: OverlapQueue,
+			run: func(ctx context.Context) error {
+				time.Sleep(time.Millisecond * 30)
+				return nil
+			},
+			wantRuns: func(runs int32) bool { return runs >= 1 && runs <= 4 },
+			wantLog:  "job is already queued, skip activation",
+		},
+		{
+			name:    "test recover panic",
+			overlap: OverlapSkip,
+			run: func(ctx context.Context) error {
+				panic("st went wrong")
+			},
+			wantRuns: func(runs int32) bool { return runs > 1 },
+			wantLog:  `"msg":"recover","job":"test","reason":"st went wrong"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				runs atomic.Int32
+				buf  = &syncBuffer{}
+			)
+			s := New(WithLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+			err := s.Add(Job{
+				Name:     "test",
+				Schedule: Every(time.Millisecond * 5),
+				Overlap:  tc.overlap,
+				Run: func(ctx context.Context) error {
+					runs.Add(1)
+					return tc.run(ctx)
+				},
+			})
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+
+			go s.Start(context.Background())
+			time.Sleep(time.Millisecond * 60)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if err := s.Shutdown(ctx); err != nil {
+				t.Fatalf("Shutdown() error = %v", err)
+			}
+
+			if got := runs.Load(); !tc.wantRuns(got) {
+				t.Errorf("runs = %d", got)
+			}
+			if !strings.Contains(buf.String(), tc.wantLog) {
+				t.Errorf("want log: %s, got: %s", tc.wantLog, buf.String())
+			}
+		})
+	}
+}
+
+func TestScheduler_SkipOverlapConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning atomic.Int32
+	s := New(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	err := s.Add(Job{
+		Name:     "busy",
+		Schedule: Every(time.Microsecond),
+		Run: func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for m := maxRunning.Load(); n > m && !maxRunning.CompareAndSwap(m, n); m = maxRunning.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	s.Start(ctx)
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := maxRunning.Load(); got != 1 {
+		t.Errorf("max concurrent runs = %d, want 1", got)
+	}
+}
+
+func TestScheduler_Add(t *testing.T) {
+	t.Parallel()
+
+	run := func(context.Context) error { return nil }
+	testCases := []struct {
+		name string
+		job  Job
+	}{
+		{name: "test no run func", job: Job{Name: "test", Schedule: Every(time.Second)}},
+		{name: "test zero interval", job: Job{Name: "test", Schedule: Every(0), Run: run}},
+		{name: "test negative interval", job: Job{Name: "test", Schedule: Every(-time.Second), Run: run}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if err := New().Add(tc.job); !errors.Is(err, errInvalidJob) {
+				t.Errorf("Add() error = %v, want %v", err, errInvalidJob)
+			}
+		})
+	}
+}
+
+func TestNextActivation(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, time.January, 10, 10, 7, 0, 0, time.UTC)
+	cron, err := Cron("* * * * *")
+	if err != nil {
+		t.Fatalf("Cron() error = %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		jitter   time.Duration
+		want     time.Time
+	}{
+		{
+			name:     "test interval does not drift by the jitter",
+			schedule: Every(time.Minute),
+			now:      at.Add(time.Second * 20),
+			jitter:   time.Second * 30,
+			want:     at.Add(time.Minute),
+		},
+		{
+			name:     "test cron with a jitter over a minute keeps every activation",
+			schedule: cron,
+			now:      at.Add(time.Second * 80),
+			jitter:   time.Second * 90,
+			want:     at.Add(time.Minute),
+		},
+		{
+			name:     "test skip the activations missed by a late trigger",
+			schedule: cron,
+			now:      at.Add(time.Minute*3 + time.Second*10),
+			jitter:   time.Second * 30,
+			want:     at.Add(time.Minute * 4),
+		},
+		{
+			name:     "test no jitter",
+			schedule: Every(time.Minute),
+			now:      at,
+			want:     at.Add(time.Minute),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nextActivation(tc.schedule, at, tc.now, tc.jitter); !got.Equal(tc.want) {
+				t.Errorf("nextActivation() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
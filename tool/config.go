@@ -0,0 +1,49 @@
+package tool
+
+import (
+	"fmt"
+
+	"github.com/ngoctd314/common/env"
+	"github.com/spf13/cobra"
+)
+
+// ConfigCommand returns the config command which prints the effective configuration,
+// add it to the root command of the service
+//
+//	config dump -o json
+//	config explain mysql.dev.dsn
+func ConfigCommand() *cobra.Command {
+	config := &cobra.Command{
+		Use:   "config",
+		Short: "Print the effective configuration resolved from files and environment variables",
+	}
+
+	var format string
+	dump := &cobra.Command{
+		Use:   "dump",
+		Short: "Print all effective keys with their source layer and type, secrets are redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return env.Dump(cmd.OutOrStdout(), format)
+		},
+	}
+	dump.Flags().StringVarP(&format, "output", "o", env.FormatYAML, "output format, yaml or json")
+
+	explain := &cobra.Command{
+		Use:   "explain KEY",
+		Short: "Print the effective value of a key, its source layer and type",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, ok := env.Explain(args[0])
+			if !ok {
+				return fmt.Errorf("key %s is not set", args[0])
+			}
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "key: %s\nvalue: %v\ntype: %s\nsource: %s\n", entry.Key, entry.Value, entry.Type, entry.Source)
+			return err
+		},
+	}
+
+	config.AddCommand(dump, explain)
+
+	return config
+}
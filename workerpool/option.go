@@ -0,0 +1,31 @@
+package workerpool
+
+type option func(*Pool)
+
+// WithConcurrency overrides the number of workers read from env
+func WithConcurrency(concurrency int) option {
+	return func(p *Pool) {
+		if concurrency > 0 {
+			p.concurrency = concurrency
+		}
+	}
+}
+
+// WithQueueSize overrides the queue size read from env
+func WithQueueSize(queueSize int) option {
+	return func(p *Pool) {
+		if queueSize >= 0 {
+			p.queueSize = queueSize
+		}
+	}
+}
+
+// WithLogger allow use your own logger style
+// if it is not set or equal to nil, a JSON slog.Logger writing to stdout is used
+func WithLogger(l Logger) option {
+	return func(p *Pool) {
+		if l != nil {
+			p.logger = l
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ngoctd314/common/core"
+	"github.com/ngoctd314/common/env"
+)
+
+var _ core.App = (*Pool)(nil)
+
+var (
+	ErrPoolClosed     = errors.New("worker pool is closed")
+	ErrQueueFull      = errors.New("worker pool queue is full")
+	ErrTasksAbandoned = errors.New("worker pool tasks abandoned")
+)
+
+// Task is executed by a worker, ctx is canceled when the pool gives up draining at the shutdown deadline
+type Task func(ctx context.Context)
+
+// Pool runs tasks on a fixed number of workers fed by a bounded queue, it implements core.App.
+// Workers are started by New, Start only waits for the instance to stop.
+type Pool struct {
+	concurrency int
+	queueSize   int
+	logger      Logger
+
+	queue chan Task
+	// ctx of tasks, canceled when the drain deadline is exceeded
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+	// closed by Shutdown, unblocks waiting submitters
+	closing chan struct{}
+	// closed once no submitter can enqueue anymore, workers drain the queue then exit
+	stop chan struct{}
+
+	submitting sync.WaitGroup
+	workers    sync.WaitGroup
+	abandoned  atomic.Int64
+}
+
+// New creates a pool and starts its workers, the size is read from env
+//   - <prefixEnv>.concurrency: the number of workers, GOMAXPROCS by default
+//   - <prefixEnv>.queueSize: the number of tasks waiting for a worker, 1024 by default
+func New(prefixEnv string, opts ...option) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := env.Namespace(prefixEnv)
+	p := &Pool{
+		concurrency: env.GetFromWithDefault(cfg, "concurrency", runtime.GOMAXPROCS(0)),
+		queueSize:   env.GetFromWithDefault(cfg, "queueSize", 1024),
+		logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		ctx:         ctx,
+		cancel:      cancel,
+		closing:     make(chan struct{}),
+		stop:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.concurrency = max(p.concurrency, 1)
+	p.queueSize = max(p.queueSize, 0)
+	p.queue = make(chan Task, p.queueSize)
+
+	p.workers.Add(p.concurrency)
+	for range p.concurrency {
+		go p.work()
+	}
+
+	return p
+}
+
+// Submit enqueues the task, it blocks while the queue is full until ctx is done
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	if err := p.beginSubmit(); err != nil {
+		return err
+	}
+	defer p.submitting.Done()
+
+	select {
+	case p.queue <- task:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrQueueFull, ctx.Err())
+	case <-p.closing:
+		return ErrPoolClosed
+	}
+}
+
+// TrySubmit enqueues the task without blocking, it returns ErrQueueFull when the queue is full
+func (p *Pool) TrySubmit(task Task) error {
+	if err := p.beginSubmit(); err != nil {
+		return err
+	}
+	defer p.submitting.Done()
+
+	select {
+	case p.queue <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (p *Pool) beginSubmit() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.submitting.Add(1)
+
+	return nil
+}
+
+// Start blocks until ctx is done or the pool is shut down
+func (p *Pool) Start(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-p.closing:
+	}
+}
+
+// Shutdown stops accepting tasks and drains the queue until ctx is done.
+// Tasks still queued at the deadline are abandoned, the returned error wraps ErrTasksAbandoned
+// and reports how many they are.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.closing)
+	p.mu.Unlock()
+
+	p.submitting.Wait()
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	// give up draining, workers drop the tasks they dequeue from now on
+	p.cancel()
+	for drained := false; !drained; {
+		select {
+		case <-p.queue:
+			p.abandoned.Add(1)
+		default:
+			drained = true
+		}
+	}
+
+	abandoned := p.abandoned.Load()
+	p.logger.Warn("worker pool shutdown deadline exceeded", "abandoned", abandoned)
+
+	return fmt.Errorf("%w: %d tasks, %w", ErrTasksAbandoned, abandoned, ctx.Err())
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for {
+		select {
+		case task := <-p.queue:
+			p.run(task)
+		case <-p.stop:
+			// drain the queue
+			for {
+				select {
+				case task := <-p.queue:
+					p.run(task)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool) run(task Task) {
+	if p.ctx.Err() != nil {
+		p.abandoned.Add(1)
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("recover", "reason", r)
+		}
+	}()
+	task(p.ctx)
+}
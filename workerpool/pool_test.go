@@ -0,0 +1,120 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		tasks         int
+		taskDuration  time.Duration
+		deadline      time.Duration
+		wantErr       error
+		wantDone      int32
+		wantAbandoned bool
+	}{
+		{
+			name:         "test drain queued tasks",
+			tasks:        10,
+			taskDuration: time.Millisecond,
+			deadline:     time.Second,
+			wantDone:     10,
+		},
+		{
+			name:          "test abandon queued tasks at deadline",
+			tasks:         10,
+			taskDuration:  time.Millisecond * 20,
+			deadline:      time.Millisecond * 30,
+			wantErr:       ErrTasksAbandoned,
+			wantAbandoned: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var done atomic.Int32
+			pool := New("test.pool", WithConcurrency(1), WithQueueSize(tc.tasks),
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+			for range tc.tasks {
+				err := pool.Submit(context.Background(), func(ctx context.Context) {
+					time.Sleep(tc.taskDuration)
+					done.Add(1)
+				})
+				if err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.deadline)
+			defer cancel()
+			err := pool.Shutdown(ctx)
+			if (err != nil || tc.wantErr != nil) && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Shutdown() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantAbandoned && done.Load() != tc.wantDone {
+				t.Errorf("done = %d, want %d", done.Load(), tc.wantDone)
+			}
+			if tc.wantAbandoned && pool.abandoned.Load() == 0 {
+				t.Error("abandoned = 0, want abandoned tasks")
+			}
+
+			if err := pool.Submit(context.Background(), func(ctx context.Context) {}); !errors.Is(err, ErrPoolClosed) {
+				t.Errorf("Submit() after Shutdown error = %v, want %v", err, ErrPoolClosed)
+			}
+		})
+	}
+}
+
+func TestPool_Submit(t *testing.T) {
+	t.Parallel()
+
+	pool := New("test.pool", WithConcurrency(1), WithQueueSize(1),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	defer pool.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	running := make(chan struct{})
+	// occupy the only worker, then fill the queue
+	_ = pool.Submit(context.Background(), func(ctx context.Context) {
+		close(running)
+		<-release
+	})
+	<-running
+	_ = pool.Submit(context.Background(), func(ctx context.Context) {
+		panic("st went wrong")
+	})
+
+	if err := pool.TrySubmit(func(ctx context.Context) {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("TrySubmit() error = %v, want %v", err, ErrQueueFull)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	if err := pool.Submit(ctx, func(ctx context.Context) {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+
+	// the panicking task does not kill the worker
+	doneCh := make(chan struct{})
+	if err := pool.Submit(context.Background(), func(ctx context.Context) { close(doneCh) }); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	select {
+	case <-doneCh:
+	case <-time.After(time.Second):
+		t.Error("task is not run after a panicking task")
+	}
+}